@@ -1,12 +1,8 @@
 package main
 
-import (
-	"C"
-)
 import (
 	"fmt"
 	"time"
-	"unsafe"
 
 	"github.com/napi-bindings/go-node-api/napi"
 )
@@ -18,19 +14,8 @@ func unixNano(env napi.Env, info napi.CallbackInfo) napi.Value {
 	return value
 }
 
-//export Initialize
-func Initialize(env unsafe.Pointer, exports unsafe.Pointer) unsafe.Pointer {
-	fmt.Println("Initialize ...")
-	caller := &napi.Caller{
-		Cb: unixNano,
-	}
-	desc := napi.Property{
-		Name:   "unixNano",
-		Method: caller,
-	}
-	props := []napi.Property{desc}
-	napi.DefineProperties((napi.Env)(env), (napi.Value)(exports), props)
-	return exports
+func init() {
+	napi.Module.Function("unixNano", unixNano)
 }
 
 func main() {}
@@ -0,0 +1,164 @@
+package napi
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"reflect"
+)
+
+// BigInt values
+// JavaScript BigInts of any size are converted to and from *big.Int values:
+//  total, status := napi.GetValueBigInt(env, value)
+//  total.Add(total, amount)
+//  res, status := napi.CreateBigInt(env, total)
+// Values known to fit in 64 bits are read faster with GetValueBigintInt64 and
+// GetValueBigintUInt64, that report whether the value has been truncated.
+// ToJS converts big.Int values to BigInts, and FromJS converts BigInts to
+// big.Int values, to integers that can hold them exactly and to *big.Int for
+// empty interfaces. The elements of BigInt64Array and BigUint64Array are
+// available as []int64 and []uint64, see GetBigInt64Array.
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// CreateBigInt function creates a JavaScript BigInt from a big.Int.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The integer to convert. A nil value is converted to 0n.
+func CreateBigInt(env Env, value *big.Int) (Value, Status) {
+	if value == nil {
+		return CreateBigintInt64(env, 0)
+	}
+	if value.IsInt64() {
+		return CreateBigintInt64(env, value.Int64())
+	}
+	sign := 0
+	if value.Sign() < 0 {
+		sign = 1
+	}
+	return CreateBigintWords(env, sign, bigWords(value))
+}
+
+// GetValueBigInt function returns the big.Int equivalent of a JavaScript
+// BigInt, without truncating it.
+// [in] env: The environment that the API is invoked under.
+// [in] value: napi_value representing JavaScript BigInt.
+// Returns napi_ok if the API succeeded. If a non-BigInt is passed in it returns
+// napi_bigint_expected.
+func GetValueBigInt(env Env, value Value) (*big.Int, Status) {
+	// Most BigInts fit in 64 bits and are read with a single call.
+	i, lossless, status := GetValueBigintInt64(env, value)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if lossless {
+		return big.NewInt(i), status
+	}
+	words, sign, status := GetValueBigintWords(env, value)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	res := setBigWords(new(big.Int), words)
+	if sign != 0 {
+		res.Neg(res)
+	}
+	return res, status
+}
+
+// bigWords returns the absolute value of x as little-endian 64-bit words.
+func bigWords(x *big.Int) []uint64 {
+	b := x.Bits()
+	if bits.UintSize == 64 {
+		words := make([]uint64, len(b))
+		for i, w := range b {
+			words[i] = uint64(w)
+		}
+		return words
+	}
+	words := make([]uint64, (len(b)+1)/2)
+	for i, w := range b {
+		words[i/2] |= uint64(w) << (32 * uint(i%2))
+	}
+	return words
+}
+
+// setBigWords sets x to the value of the little-endian 64-bit words.
+func setBigWords(x *big.Int, words []uint64) *big.Int {
+	if bits.UintSize == 64 {
+		b := make([]big.Word, len(words))
+		for i, w := range words {
+			b[i] = big.Word(w)
+		}
+		return x.SetBits(b)
+	}
+	b := make([]big.Word, 2*len(words))
+	for i, w := range words {
+		b[2*i] = big.Word(w & math.MaxUint32)
+		b[2*i+1] = big.Word(w >> 32)
+	}
+	return x.SetBits(b)
+}
+
+// bigIntToJS converts a big.Int value.
+func bigIntToJS(env Env, rv reflect.Value, path string) (Value, error) {
+	var x *big.Int
+	if rv.CanAddr() {
+		x = rv.Addr().Interface().(*big.Int)
+	} else {
+		v := rv.Interface().(big.Int)
+		x = &v
+	}
+	res, status := CreateBigInt(env, x)
+	return checkValue(env, res, status, path)
+}
+
+// bigIntFromJS converts a BigInt, or a number holding an integer, to a
+// big.Int value.
+func bigIntFromJS(env Env, value Value, vt ValueType, rv reflect.Value, path string) error {
+	var x *big.Int
+	switch vt {
+	case ValueType(ValueTypes.Bigint):
+		var status Status
+		if x, status = GetValueBigInt(env, value); status != Status(Statuses.OK) {
+			return statusError(env, path, status)
+		}
+	case ValueType(ValueTypes.Number):
+		f, err := numberFromJS(env, value, vt, path)
+		if err != nil {
+			return err
+		}
+		if math.IsInf(f, 0) || math.Trunc(f) != f {
+			return conversionError(path, "expected integer")
+		}
+		x, _ = big.NewFloat(f).Int(nil)
+	default:
+		return conversionError(path, "expected bigint")
+	}
+	rv.Set(reflect.ValueOf(x).Elem())
+	return nil
+}
+
+// bigIntegerFromJS converts a BigInt to an integer of the kind of rv, that
+// must hold it exactly.
+func bigIntegerFromJS(env Env, value Value, rv reflect.Value, path string) error {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, lossless, status := GetValueBigintUInt64(env, value)
+		if status != Status(Statuses.OK) {
+			return statusError(env, path, status)
+		}
+		if !lossless || rv.OverflowUint(u) {
+			return conversionError(path, "bigint overflows %s", rv.Type())
+		}
+		rv.SetUint(u)
+	default:
+		i, lossless, status := GetValueBigintInt64(env, value)
+		if status != Status(Statuses.OK) {
+			return statusError(env, path, status)
+		}
+		if !lossless || rv.OverflowInt(i) {
+			return conversionError(path, "bigint overflows %s", rv.Type())
+		}
+		rv.SetInt(i)
+	}
+	return nil
+}
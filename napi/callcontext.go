@@ -0,0 +1,302 @@
+package napi
+
+/*
+#include <node_api.h>
+*/
+import "C"
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Call context
+// A CallContext gives access to the details of a call from JavaScript: the
+// arguments, this, new.target and the data of the callback. The arguments
+// are read with typed getters, that return their default value when the
+// argument is missing or undefined, like the default parameters of
+// JavaScript functions:
+//  func(ctx *napi.CallContext) napi.Value {
+//  	name, err := ctx.Arg(0).String("")
+//  	if err != nil {
+//  		return ctx.ThrowTypeError(err.Error())
+//  	}
+//  	retries, err := ctx.Arg(1).Int(3)
+//  	if err != nil {
+//  		return ctx.ThrowTypeError(err.Error())
+//  	}
+//  	...
+//  }
+// The details of the call are read on first use, with a single N-API call for
+// the arguments, this and the data, so that calls with up to inlineArgs
+// arguments do not allocate them. new.target is only read if needed.
+
+// inlineArgs is the number of arguments stored in the CallContext itself.
+const inlineArgs = 6
+
+// CallContext describes a call of a native function from JavaScript. It is
+// only valid during the call.
+type CallContext struct {
+	env          Env
+	info         CallbackInfo
+	status       Status
+	loaded       bool
+	args         []Value
+	inline       [inlineArgs]Value
+	this         Value
+	data         unsafe.Pointer
+	target       Value
+	targetLoaded bool
+	undefined    Value
+}
+
+// newCallContext returns the context of the call described by info.
+func newCallContext(env Env, info CallbackInfo) *CallContext {
+	return &CallContext{env: env, info: info}
+}
+
+// load reads the arguments, this and the data of the call, once.
+func (ctx *CallContext) load() {
+	if ctx.loaded {
+		return
+	}
+	ctx.loaded = true
+	var argc C.size_t = inlineArgs
+	var this C.napi_value
+	var data unsafe.Pointer
+	var status = C.napi_get_cb_info(ctx.env, ctx.info, &argc, valuesPointer(ctx.inline[:]), &this, &data)
+	if status != C.napi_ok {
+		ctx.status = Status(status)
+		return
+	}
+	if argc > inlineArgs {
+		ctx.args = make([]Value, int(argc))
+		status = C.napi_get_cb_info(ctx.env, ctx.info, &argc, valuesPointer(ctx.args), nil, nil)
+		if status != C.napi_ok {
+			ctx.status, ctx.args = Status(status), nil
+			return
+		}
+	} else {
+		ctx.args = ctx.inline[:argc]
+	}
+	ctx.this = Value(this)
+	// The data pointer of the callbacks is the handle of their registry entry.
+	ctx.data = callbackData(handle(uintptr(data)))
+	ctx.status = Status(status)
+}
+
+// Env returns the environment that the function is invoked under.
+func (ctx *CallContext) Env() Env {
+	return ctx.env
+}
+
+// Info returns the callback info of the call, to be used with the N-API
+// functions that take one.
+func (ctx *CallContext) Info() CallbackInfo {
+	return ctx.info
+}
+
+// Status returns the status of the N-API calls that read the details of the
+// call. If it is not napi_ok, the call has no arguments, this or data, or its
+// new.target could not be read.
+func (ctx *CallContext) Status() Status {
+	ctx.load()
+	return ctx.status
+}
+
+// Len returns the number of arguments of the call.
+func (ctx *CallContext) Len() int {
+	ctx.load()
+	return len(ctx.args)
+}
+
+// Args returns the arguments of the call. The slice must not be modified.
+func (ctx *CallContext) Args() []Value {
+	ctx.load()
+	return ctx.args
+}
+
+// Arg returns the argument of the call at index i, that is undefined if the
+// function has been called with fewer arguments.
+func (ctx *CallContext) Arg(i int) Arg {
+	ctx.load()
+	arg := Arg{ctx: ctx, index: i}
+	if i >= 0 && i < len(ctx.args) {
+		arg.value = ctx.args[i]
+	}
+	return arg
+}
+
+// This returns the this value of the call.
+func (ctx *CallContext) This() Value {
+	ctx.load()
+	return ctx.this
+}
+
+// Data returns the data of the callback, that is the Data of the Property for
+// methods and accessors.
+func (ctx *CallContext) Data() unsafe.Pointer {
+	ctx.load()
+	return ctx.data
+}
+
+// NewTarget returns the new.target of the call, that is nil unless the
+// function is called as a constructor. It is also nil if it cannot be read,
+// and Status then returns the status of the failed N-API call.
+func (ctx *CallContext) NewTarget() Value {
+	if !ctx.targetLoaded {
+		ctx.targetLoaded = true
+		target, status := GetNewTarget(ctx.env, ctx.info)
+		if status != Status(Statuses.OK) {
+			ctx.load()
+			ctx.status = status
+			return nil
+		}
+		ctx.target = target
+	}
+	return ctx.target
+}
+
+// IsConstructCall reports whether the function is called as a constructor,
+// like new fn(). Status must be checked when it returns false, since
+// new.target is nil if it cannot be read.
+func (ctx *CallContext) IsConstructCall() bool {
+	return ctx.NewTarget() != nil
+}
+
+// Undefined returns the undefined value.
+func (ctx *CallContext) Undefined() Value {
+	if ctx.undefined == nil {
+		ctx.undefined, _ = GetUndefined(ctx.env)
+	}
+	return ctx.undefined
+}
+
+// Throw throws err as described for ThrowGoError and returns nil, the value
+// to return from the callback.
+func (ctx *CallContext) Throw(err error) Value {
+	ThrowGoError(ctx.env, err)
+	return nil
+}
+
+// ThrowError throws an Error with the message msg and returns nil, the value
+// to return from the callback.
+func (ctx *CallContext) ThrowError(msg string) Value {
+	ThrowError(ctx.env, msg, "")
+	return nil
+}
+
+// ThrowTypeError throws a TypeError with the message msg and returns nil, the
+// value to return from the callback.
+func (ctx *CallContext) ThrowTypeError(msg string) Value {
+	ThrowTypeError(ctx.env, msg, "")
+	return nil
+}
+
+// ThrowRangeError throws a RangeError with the message msg and returns nil,
+// the value to return from the callback.
+func (ctx *CallContext) ThrowRangeError(msg string) Value {
+	ThrowRangError(ctx.env, msg, "")
+	return nil
+}
+
+// Arg is an argument of a call. The typed getters convert it as described
+// for FromJS, returning a *ConversionError if it cannot be converted, and
+// return their default value if the argument is missing or undefined.
+type Arg struct {
+	ctx   *CallContext
+	index int
+	value Value
+}
+
+// Value returns the value of the argument, that is undefined if the argument
+// is missing.
+func (a Arg) Value() Value {
+	if a.value == nil {
+		return a.ctx.Undefined()
+	}
+	return a.value
+}
+
+// IsUndefined reports whether the argument is missing or undefined.
+func (a Arg) IsUndefined() bool {
+	if a.value == nil {
+		return true
+	}
+	vt, status := TypeOf(a.ctx.env, a.value)
+	return status == Status(Statuses.OK) && vt == ValueType(ValueTypes.Undefined)
+}
+
+// Bool returns the argument as a bool, or def if it is undefined.
+func (a Arg) Bool(def bool) (bool, error) {
+	err := a.into(&def)
+	return def, err
+}
+
+// Int returns the argument as an int, or def if it is undefined.
+func (a Arg) Int(def int) (int, error) {
+	err := a.into(&def)
+	return def, err
+}
+
+// Int64 returns the argument as an int64, or def if it is undefined.
+func (a Arg) Int64(def int64) (int64, error) {
+	err := a.into(&def)
+	return def, err
+}
+
+// Uint32 returns the argument as a uint32, or def if it is undefined.
+func (a Arg) Uint32(def uint32) (uint32, error) {
+	err := a.into(&def)
+	return def, err
+}
+
+// Float64 returns the argument as a float64, or def if it is undefined.
+func (a Arg) Float64(def float64) (float64, error) {
+	err := a.into(&def)
+	return def, err
+}
+
+// String returns the argument as a string, or def if it is undefined.
+func (a Arg) String(def string) (string, error) {
+	err := a.into(&def)
+	return def, err
+}
+
+// Into converts the argument into the Go value pointed to by dst, as FromJS
+// does. dst is left untouched if the argument is undefined.
+func (a Arg) Into(dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return conversionError(a.path(), "Into requires a non-nil pointer, not %T", dst)
+	}
+	return a.into(dst)
+}
+
+// into converts the argument into the value pointed to by dst, unless it is
+// undefined. The value is converted first, so that dst keeps its default if
+// the conversion fails. The type of the argument is read once, and reused by
+// the conversion.
+func (a Arg) into(dst interface{}) error {
+	if a.value == nil {
+		return nil
+	}
+	vt, status := TypeOf(a.ctx.env, a.value)
+	if status != Status(Statuses.OK) {
+		return statusError(a.ctx.env, a.path(), status)
+	}
+	if vt == ValueType(ValueTypes.Undefined) {
+		return nil
+	}
+	rv := reflect.ValueOf(dst).Elem()
+	v := reflect.New(rv.Type()).Elem()
+	if err := typedFromJS(a.ctx.env, a.value, vt, v, a.path()); err != nil {
+		return err
+	}
+	rv.Set(v)
+	return nil
+}
+
+// path returns the path of the argument in conversion errors.
+func (a Arg) path() string {
+	return indexPath("args", a.index)
+}
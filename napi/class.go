@@ -0,0 +1,293 @@
+package napi
+
+/*
+#include <stdlib.h>
+#include "gonapi.h"
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+	"unsafe"
+)
+
+// Binding Go types as classes
+// DefineClassFor turns a Go struct type into a JavaScript class. The class is
+// described by its constructor, a Go function returning a pointer to the
+// struct:
+//  type Counter struct {
+//  	Step  int
+//  	count int
+//  }
+//  func NewCounter(step int) *Counter { return &Counter{Step: step} }
+//  func (c *Counter) Increment() int  { c.count += c.Step; return c.count }
+// The constructor follows the same rules as the functions exported with Export
+// and is called by `new Counter(2)`. The exported methods of the pointer type
+// become methods of the prototype and the exported fields become accessors,
+// both named in lower camel case (`increment`, `step`) unless the field has a
+// `json` tag. Methods whose results are not supported by Export, like
+// Pair() (int, int), are skipped. Every instance wraps the Go value returned
+// by the constructor, that is released when the JavaScript object is
+// garbage-collected. Instances passed back to Go functions are converted to
+// the wrapped value.
+
+// InvalidThisErrorCode is the code set on the TypeError thrown when a method or
+// an accessor of a bound class is called on an object that is not an instance
+// of the class.
+const InvalidThisErrorCode = "ERR_INVALID_THIS"
+
+// wrappedValue contains the Go value wrapped in an instance of a bound class.
+type wrappedValue struct {
+	value reflect.Value
+}
+
+// classType describes a Go type bound as a JavaScript class.
+type classType struct {
+	name    string
+	typ     reflect.Type
+	ctor    *goFunc
+	methods []classMethod
+	fields  []structField
+}
+
+// classMethod describes a method of a bound class.
+type classMethod struct {
+	name string
+	fn   *goFunc
+}
+
+// DefineClassFor function defines a JavaScript class bound to the Go type
+// returned by constructor.
+// [in] env: The environment that the API is invoked under.
+// [in] name: Name of the JavaScript constructor function.
+// [in] constructor: The Go function that creates the instances. It must return
+// a pointer to a struct, optionally followed by an error.
+// It panics if constructor is not supported.
+func DefineClassFor(env Env, name string, constructor interface{}) (Value, Status) {
+	return newClassType(name, constructor).define(env)
+}
+
+// UnwrapObject function returns the Go value wrapped in an instance of a class
+// defined with DefineClassFor.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The instance of the class.
+func UnwrapObject(env Env, value Value) (interface{}, Status) {
+	wrapped, ok := unwrapValue(env, value)
+	if !ok {
+		return nil, Status(C.napi_invalid_arg)
+	}
+	return wrapped.Interface(), Status(C.napi_ok)
+}
+
+// unwrapValue returns the Go value wrapped in the object, if any.
+func unwrapValue(env Env, value Value) (reflect.Value, bool) {
+	var res C.uintptr_t
+	var status = C.napi_unwrap(env, value, (*unsafe.Pointer)(unsafe.Pointer(&res)))
+	if status != C.napi_ok {
+		return reflect.Value{}, false
+	}
+	entry, ok := callbacks.get(handle(res))
+	if !ok {
+		return reflect.Value{}, false
+	}
+	wrapped, ok := entry.(*wrappedValue)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return wrapped.value, true
+}
+
+// newClassType inspects the constructor and the type it returns, panicking if
+// they are not supported.
+func newClassType(name string, constructor interface{}) *classType {
+	ctor := newGoFunc(name, constructor)
+	t := ctor.fn.Type()
+	if !ctor.hasValue || t.Out(0).Kind() != reflect.Ptr || t.Out(0).Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("napi: constructor of %s must return a pointer to a struct, not %s", name, t))
+	}
+	c := &classType{
+		name: name,
+		typ:  t.Out(0),
+		ctor: ctor,
+	}
+	names := make(map[string]bool)
+	for i := 0; i < c.typ.NumMethod(); i++ {
+		m := c.typ.Method(i)
+		if _, _, ok := funcResults(m.Type); !ok {
+			// Methods with other results, like Pair() (int, int), are not
+			// exposed rather than making the whole type unusable.
+			continue
+		}
+		method := jsName(m.Name)
+		names[method] = true
+		c.methods = append(c.methods, classMethod{
+			name: method,
+			fn:   newGoFuncValue(name+"."+method, m.Func, true),
+		})
+	}
+	for _, field := range cachedFields(c.typ.Elem()) {
+		switch field.typ.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+			continue
+		}
+		if !field.tagged {
+			field.name = jsName(field.name)
+		}
+		if names[field.name] {
+			panic(fmt.Sprintf("napi: field %s of %s collides with a method", field.name, name))
+		}
+		names[field.name] = true
+		c.fields = append(c.fields, field)
+	}
+	return c
+}
+
+// jsName converts the name of an exported Go identifier to lower camel case,
+// keeping initialisms together: ID becomes id and URLPath becomes urlPath.
+func jsName(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// define defines the JavaScript class in the environment.
+func (c *classType) define(env Env) (Value, Status) {
+	properties := make([]Property, 0, len(c.methods)+len(c.fields))
+	for _, method := range c.methods {
+		properties = append(properties, Property{
+			Name:   method.name,
+			Method: &Caller{Cb: c.method(method.fn)},
+		})
+	}
+	for _, field := range c.fields {
+		properties = append(properties, Property{
+			Name:       field.name,
+			Getter:     &Caller{Cb: c.getter(field)},
+			Setter:     &Caller{Cb: c.setter(field)},
+			Attributes: PropertyAttributes.Enumerable | PropertyAttributes.Configurable,
+		})
+	}
+	return DefineClass(env, c.name, &Caller{Cb: c.construct}, properties)
+}
+
+// construct is the constructor of the class. It calls the Go constructor and
+// wraps the value it returns in the new object.
+func (c *classType) construct(env Env, info CallbackInfo) Value {
+	ctx := newCallContext(env, info)
+	// Status also reports the failure to read new.target.
+	construct := ctx.IsConstructCall()
+	if ctx.Status() != Status(Statuses.OK) {
+		return nil
+	}
+	if !construct {
+		return ctx.ThrowTypeError(fmt.Sprintf("Class constructor %s cannot be invoked without 'new'", c.name))
+	}
+	this := ctx.This()
+	out, ok := c.ctor.invoke(env, reflect.Value{}, ctx.Args())
+	if !ok {
+		return nil
+	}
+	if out[0].IsNil() {
+		ThrowError(env, c.name+": constructor returned nil", "")
+		return nil
+	}
+	h := callbacks.add(&wrappedValue{value: out[0]})
+	if status := C.WrapHandle(env, this, C.uintptr_t(h)); status != C.napi_ok {
+		callbacks.remove(h)
+		ThrowError(env, c.name+": failed to wrap the instance", "")
+		return nil
+	}
+	return this
+}
+
+// receiver returns the Go value wrapped in this, throwing a TypeError if this
+// is not an instance of the class.
+func (c *classType) receiver(env Env, this Value) (reflect.Value, bool) {
+	recv, ok := unwrapValue(env, this)
+	if !ok || recv.Type() != c.typ {
+		ThrowTypeError(env, "Illegal invocation", InvalidThisErrorCode)
+		return reflect.Value{}, false
+	}
+	return recv, true
+}
+
+func (c *classType) method(fn *goFunc) CCallback {
+	return func(env Env, info CallbackInfo) Value {
+		ctx := newCallContext(env, info)
+		if ctx.Status() != Status(Statuses.OK) {
+			return nil
+		}
+		recv, ok := c.receiver(env, ctx.This())
+		if !ok {
+			return nil
+		}
+		return fn.call(env, recv, ctx.Args())
+	}
+}
+
+func (c *classType) getter(field structField) CCallback {
+	return func(env Env, info CallbackInfo) Value {
+		ctx := newCallContext(env, info)
+		if ctx.Status() != Status(Statuses.OK) {
+			return nil
+		}
+		recv, ok := c.receiver(env, ctx.This())
+		if !ok {
+			return nil
+		}
+		fv, ok := fieldByIndex(recv.Elem(), field.index, false)
+		if !ok {
+			res, _ := GetUndefined(env)
+			return res
+		}
+		res, err := toJS(env, fv, field.name)
+		if err != nil {
+			ThrowError(env, c.name+": "+err.Error(), "")
+			return nil
+		}
+		return res
+	}
+}
+
+func (c *classType) setter(field structField) CCallback {
+	return func(env Env, info CallbackInfo) Value {
+		ctx := newCallContext(env, info)
+		if status := ctx.Status(); status != Status(Statuses.OK) {
+			ThrowTypeError(env, c.name+": "+statusError(env, field.name, status).Error(), ArgumentTypeErrorCode)
+			return nil
+		}
+		if ctx.Len() == 0 {
+			ThrowTypeError(env, fmt.Sprintf("%s: missing value for %s", c.name, field.name), ArgumentTypeErrorCode)
+			return nil
+		}
+		recv, ok := c.receiver(env, ctx.This())
+		if !ok {
+			return nil
+		}
+		// The value is converted first, so the field is left untouched if the
+		// conversion fails.
+		v := reflect.New(field.typ).Elem()
+		if err := fromJS(env, ctx.Arg(0).Value(), v, field.name); err != nil {
+			ThrowTypeError(env, c.name+": "+err.Error(), ArgumentTypeErrorCode)
+			return nil
+		}
+		fv, ok := fieldByIndex(recv.Elem(), field.index, true)
+		if !ok {
+			msg := fmt.Sprintf("%s: cannot set embedded pointer to unexported struct %s", c.name, embeddedType(c.typ.Elem(), field.index))
+			ThrowTypeError(env, msg, "")
+			return nil
+		}
+		fv.Set(v)
+		return nil
+	}
+}
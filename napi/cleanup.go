@@ -0,0 +1,202 @@
+package napi
+
+/*
+#include "gonapi.h"
+*/
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// Cleanup on exit of the current Node.js instance
+// While a Node.js process typically releases all its resources when exiting,
+// embedders of Node.js, or future Worker support, may require addons to
+// register clean-up hooks that will be run once the current Node.js instance
+// exits.
+// N-API provides functions for registering and un-registering such callbacks.
+// When those callbacks are run, all resources that are being held by the addon
+// should be freed up.
+// Cleanup hooks run synchronously, in reverse order of registration. Async
+// cleanup hooks allow the add-on to release its resources asynchronously, for
+// example to shut down goroutines, close thread-safe functions and flush state:
+// the environment is not destroyed until every async cleanup hook has signaled
+// that it is done.
+
+// CleanupHook represents a function called when the environment is torn
+// down. No JavaScript code can be executed from the hook.
+type CleanupHook func()
+
+// AsyncCleanupHook represents a function called when the environment is torn
+// down, that can complete the cleanup asynchronously. The function must call
+// done exactly once when the cleanup is completed. done can be called from any
+// goroutine, either before or after the hook returns.
+type AsyncCleanupHook func(done func())
+
+// CleanupHookHandle identifies a cleanup hook that has been added to an
+// environment. It is used to remove the hook before the environment is torn
+// down.
+type CleanupHookHandle struct {
+	h handle
+}
+
+// cleanupHookEntry contains a cleanup hook and the environment it has been
+// added to.
+type cleanupHookEntry struct {
+	env    Env
+	hook   CleanupHook
+	async  AsyncCleanupHook
+	remove C.napi_async_cleanup_hook_handle
+}
+
+// AddEnvCleanupHook function registers fn as a function to be run once the
+// current Node.js environment exits.
+// [in] env: The environment that the API is invoked under.
+// [in] fn: The function to call when the environment is being torn down.
+// A function can safely be specified multiple times, every registration gets
+// its own handle. The returned handle can be used to remove the hook with
+// RemoveEnvCleanupHook.
+// N-API version: 3
+func AddEnvCleanupHook(env Env, fn CleanupHook) (*CleanupHookHandle, Status) {
+	h := callbacks.add(&cleanupHookEntry{
+		env:  env,
+		hook: fn,
+	})
+	var status = C.AddEnvCleanupHook(env, C.uintptr_t(h))
+	if status != C.napi_ok {
+		callbacks.remove(h)
+		return nil, Status(status)
+	}
+	return &CleanupHookHandle{h: h}, Status(status)
+}
+
+// RemoveEnvCleanupHook function unregisters a hook added with
+// AddEnvCleanupHook. The hook will not be called when the environment is torn
+// down.
+// [in] env: The environment that the API is invoked under.
+// [in] hook: The handle returned by AddEnvCleanupHook.
+// N-API version: 3
+func RemoveEnvCleanupHook(env Env, hook *CleanupHookHandle) Status {
+	if hook == nil {
+		return Status(C.napi_invalid_arg)
+	}
+	var status = C.RemoveEnvCleanupHook(env, C.uintptr_t(hook.h))
+	if status == C.napi_ok {
+		callbacks.remove(hook.h)
+	}
+	return Status(status)
+}
+
+// AddAsyncCleanupHook function registers fn as a function to be run once the
+// current Node.js environment exits. Unlike AddEnvCleanupHook the hook can
+// complete asynchronously, calling the done function it receives.
+// [in] env: The environment that the API is invoked under.
+// [in] fn: The function to call when the environment is being torn down.
+// The returned handle can be used to remove the hook with
+// RemoveAsyncCleanupHook.
+// N-API version: 8
+func AddAsyncCleanupHook(env Env, fn AsyncCleanupHook) (*CleanupHookHandle, Status) {
+	entry := &cleanupHookEntry{
+		env:   env,
+		async: fn,
+	}
+	h := callbacks.add(entry)
+	var status = C.AddAsyncCleanupHook(env, C.uintptr_t(h), &entry.remove)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+		return nil, Status(status)
+	}
+	return &CleanupHookHandle{h: h}, Status(status)
+}
+
+// RemoveAsyncCleanupHook function unregisters a hook added with
+// AddAsyncCleanupHook. The hook will not be called when the environment is
+// torn down.
+// [in] hook: The handle returned by AddAsyncCleanupHook.
+// N-API version: 8
+func RemoveAsyncCleanupHook(hook *CleanupHookHandle) Status {
+	if hook == nil {
+		return Status(C.napi_invalid_arg)
+	}
+	entry, ok := callbacks.get(hook.h)
+	if !ok {
+		return Status(C.napi_invalid_arg)
+	}
+	var status = C.napi_remove_async_cleanup_hook(entry.(*cleanupHookEntry).remove)
+	if status == C.napi_ok {
+		callbacks.remove(hook.h)
+	}
+	return Status(status)
+}
+
+//export CallEnvCleanupHook
+func CallEnvCleanupHook(wrap C.uintptr_t) {
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	defer callbacks.remove(handle(wrap))
+	hook := entry.(*cleanupHookEntry)
+	defer recoverFatal(hook.env)
+	hook.hook()
+}
+
+//export CallAsyncCleanupHook
+func CallAsyncCleanupHook(wrap C.uintptr_t, remove C.napi_async_cleanup_hook_handle) {
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		C.napi_remove_async_cleanup_hook(remove)
+		return
+	}
+	hook := entry.(*cleanupHookEntry)
+	complete := func() {
+		callbacks.remove(handle(wrap))
+		C.napi_remove_async_cleanup_hook(remove)
+	}
+	// The hook must be removed from the main thread, while done can be called
+	// from any goroutine: done releases a thread-safe function, whose
+	// finalizer removes the hook on the main thread. Without it the hook is
+	// removed as soon as it returns, so that the environment is never kept
+	// waiting.
+	signal, status := newCleanupSignal(hook.env, complete)
+	if status != Status(Statuses.OK) {
+		defer complete()
+	}
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			if status == Status(Statuses.OK) {
+				ReleaseThreadsafeFunction(signal, TheradsafeFunctionReleaseMode(TsfnReleaseMode.NapiTsfnRelease))
+			}
+		})
+	}
+	defer recoverFatal(hook.env)
+	hook.async(done)
+}
+
+// newCleanupSignal creates the thread-safe function released by the done
+// function of an async cleanup hook, that calls complete once it is
+// finalized. It is created by the hook, so that it is not closed by the
+// teardown of the environment before the hook is done.
+func newCleanupSignal(env Env, complete func()) (ThreadsafeFunction, Status) {
+	scope, status := OnpenHandleScope(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	defer CloseHandleScope(env, scope)
+	name, status := CreateStringUtf8(env, "napi.AsyncCleanupHook")
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	finalizer := &FinalizeCaller{
+		Cb: func(env Env, data unsafe.Pointer, hint unsafe.Pointer) {
+			complete()
+		},
+	}
+	// The function is never called, but N-API requires either a JavaScript
+	// function or a callback.
+	call := &ThreadsafeFunctionsCaller{
+		Cb: func(env Env, fn Value, context unsafe.Pointer, data unsafe.Pointer) {},
+	}
+	return CreateThreadsafeFunction(env, nil, nil, name, 0, 1, nil, finalizer, nil, call)
+}
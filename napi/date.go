@@ -0,0 +1,121 @@
+package napi
+
+import (
+	"math"
+	"reflect"
+	"time"
+)
+
+// Dates
+// JavaScript Dates are converted to and from time.Time values with CreateTime
+// and GetValueTime, and by ToJS and FromJS. A Date is an instant counted in
+// milliseconds since the Unix epoch, without time zone, so that:
+//  - times are truncated to the millisecond before them, towards the past;
+//  - the location of times is lost, and Dates become times in UTC, that can
+//    be converted with In or Local;
+//  - the monotonic clock reading of times, as returned by time.Now, is
+//    dropped, as only the wall clock is meaningful to JavaScript;
+//  - times out of the range of Dates, 100,000,000 days before and after the
+//    epoch, cannot be converted;
+//  - Invalid Dates, like new Date(NaN), cannot be converted.
+
+// maxDate is the maximum number of milliseconds between a Date and the epoch,
+// as defined in Section 20.3.1.1 of the ECMAScript Language Specification.
+const maxDate = 8.64e15
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// CreateTime function creates a JavaScript Date from a time.Time, truncated
+// to the millisecond.
+// [in] env: The environment that the API is invoked under.
+// [in] t: The time to convert.
+// Returns napi_invalid_arg if t is out of the range of Dates.
+func CreateTime(env Env, t time.Time) (Value, Status) {
+	ms, ok := timeMillis(t)
+	if !ok {
+		return nil, Status(Statuses.InvalidArg)
+	}
+	return CreateDate(env, ms)
+}
+
+// GetValueTime function returns the time.Time equivalent of a JavaScript
+// Date, in UTC.
+// [in] env: The environment that the API is invoked under.
+// [in] value: napi_value representing a JavaScript Date.
+// Returns napi_ok if the API succeeded. If a non-date is passed in it returns
+// napi_date_expected, and if an Invalid Date is passed in it returns
+// napi_invalid_arg.
+func GetValueTime(env Env, value Value) (time.Time, Status) {
+	ms, status := GetDateValue(env, value)
+	if status != Status(Statuses.OK) {
+		return time.Time{}, status
+	}
+	t, ok := millisTime(ms)
+	if !ok {
+		return time.Time{}, Status(Statuses.InvalidArg)
+	}
+	return t, status
+}
+
+// timeMillis returns the number of milliseconds between the epoch and t,
+// rounded towards the past, if it is in the range of Dates.
+func timeMillis(t time.Time) (float64, bool) {
+	// t.UnixNano overflows for times out of the years 1678-2262, while Unix
+	// covers the whole range of Dates.
+	sec := t.Unix()
+	if sec < -maxDate/1000 || sec > maxDate/1000 {
+		return 0, false
+	}
+	ms := float64(sec*1000 + int64(t.Nanosecond())/int64(time.Millisecond))
+	if ms < -maxDate || ms > maxDate {
+		return 0, false
+	}
+	return ms, true
+}
+
+// millisTime returns the time that is ms milliseconds after the epoch, unless
+// ms is the time value of an Invalid Date.
+func millisTime(ms float64) (time.Time, bool) {
+	if math.IsNaN(ms) || ms < -maxDate || ms > maxDate {
+		return time.Time{}, false
+	}
+	n := int64(ms)
+	sec, rem := n/1000, n%1000
+	if rem < 0 {
+		sec--
+		rem += 1000
+	}
+	return time.Unix(sec, rem*int64(time.Millisecond)).UTC(), true
+}
+
+// timeToJS converts a time.Time value.
+func timeToJS(env Env, rv reflect.Value, path string) (Value, error) {
+	t := rv.Interface().(time.Time)
+	ms, ok := timeMillis(t)
+	if !ok {
+		return nil, conversionError(path, "time %s is out of the range of dates", t)
+	}
+	res, status := CreateDate(env, ms)
+	return checkValue(env, res, status, path)
+}
+
+// timeFromJS converts a Date to a time.Time value.
+func timeFromJS(env Env, value Value, rv reflect.Value, path string) error {
+	isDate, status := IsDate(env, value)
+	if status != Status(Statuses.OK) {
+		return statusError(env, path, status)
+	}
+	if !isDate {
+		return conversionError(path, "expected date")
+	}
+	ms, status := GetDateValue(env, value)
+	if status != Status(Statuses.OK) {
+		return statusError(env, path, status)
+	}
+	t, ok := millisTime(ms)
+	if !ok {
+		return conversionError(path, "invalid date")
+	}
+	rv.Set(reflect.ValueOf(t))
+	return nil
+}
@@ -0,0 +1,299 @@
+package napi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Error classes
+// An add-on can define its own subclasses of Error, that JavaScript code can
+// check with instanceof, and throw Go errors as instances of them:
+//  type ValidationError struct {
+//  	Field string `json:"field"`
+//  }
+//  func (e *ValidationError) Error() string { return "invalid " + e.Field }
+//  var validationError = &napi.ErrorClass{
+//  	Name: "ValidationError",
+//  	Code: "ERR_VALIDATION",
+//  }
+//  func init() {
+//  	napi.Module.ErrorClass(&ValidationError{}, validationError)
+//  }
+// Any error whose chain contains a *ValidationError is then thrown as a
+// ValidationError, whose field property is set from the Go error. The exported
+// fields of registered error types are converted as described for ToJS,
+// except the fields named message or stack, that would overwrite the
+// properties of the error, and the fields that cannot be converted. The
+// classes can also be constructed from JavaScript, with the same arguments as
+// the class they extend.
+
+// ErrorClass describes a JavaScript subclass of Error defined from Go. The
+// constructor of the class is defined once in every environment.
+type ErrorClass struct {
+	// Name is the name of the class and of its instances.
+	Name string
+	// Extends is the name of the global class extended by the class: "Error"
+	// (the default), "TypeError", "RangeError" or any other subclass of Error.
+	Extends string
+	// Code is the default code of the instances.
+	Code string
+	// Properties are defined on the prototype of the class, converted as
+	// described for ToJS.
+	Properties map[string]interface{}
+}
+
+// This is a struct used as container for the registered error classes.
+type errorClassRegistry struct {
+	mu    sync.RWMutex
+	types map[reflect.Type]*ErrorClass
+}
+
+var errorClasses = &errorClassRegistry{
+	types: make(map[reflect.Type]*ErrorClass),
+}
+
+// errorConstructors holds the references to the constructors of the error
+// classes defined in every environment.
+var errorConstructors = &EnvLocal{
+	New: func(env Env) interface{} {
+		return make(map[*ErrorClass]Ref)
+	},
+}
+
+// RegisterErrorClass function registers the class used to throw the errors
+// having the same type as example. It panics if the type is already
+// registered.
+// [in] example: A value of the Go error type, for example &ValidationError{}.
+// [in] class: The class of the JavaScript errors.
+func RegisterErrorClass(example error, class *ErrorClass) {
+	if example == nil || class == nil {
+		panic("napi: RegisterErrorClass example or class is nil")
+	}
+	t := reflect.TypeOf(example)
+	errorClasses.mu.Lock()
+	defer errorClasses.mu.Unlock()
+	if _, dup := errorClasses.types[t]; dup {
+		panic(fmt.Sprintf("napi: error class for %s is registered more than once", t))
+	}
+	errorClasses.types[t] = class
+}
+
+// errorClassOf returns the class registered for the type of err, if any.
+func errorClassOf(err error) *ErrorClass {
+	errorClasses.mu.RLock()
+	defer errorClasses.mu.RUnlock()
+	return errorClasses.types[reflect.TypeOf(err)]
+}
+
+// Constructor returns the constructor of the class in the environment,
+// defining the class the first time it is used.
+// [in] env: The environment that the API is invoked under.
+func (c *ErrorClass) Constructor(env Env) (Value, Status) {
+	refs, status := errorConstructors.Get(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if ref, ok := refs.(map[*ErrorClass]Ref)[c]; ok {
+		return GetReferenceValue(env, ref)
+	}
+	ctor, status := c.define(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	ref, status := CreateReference(env, ctor, 1)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	refs.(map[*ErrorClass]Ref)[c] = ref
+	return ctor, status
+}
+
+// base returns the constructor of the class extended by the class.
+func (c *ErrorClass) base(env Env) (Value, Status) {
+	extends := c.Extends
+	if extends == "" {
+		extends = "Error"
+	}
+	return globalProperty(env, extends)
+}
+
+// define defines the class, making it a subclass of its base.
+func (c *ErrorClass) define(env Env) (Value, Status) {
+	base, status := c.base(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	name, status := CreateStringUtf8(env, c.Name)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	attributes := PropertyAttributes.Writable | PropertyAttributes.Configurable
+	properties := []Property{{Name: "name", Value: name, Attributes: attributes}}
+	keys := make([]string, 0, len(c.Properties))
+	for key := range c.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := ToJS(env, c.Properties[key])
+		if err != nil {
+			return nil, Status(Statuses.InvalidArg)
+		}
+		properties = append(properties, Property{Name: key, Value: value, Attributes: attributes})
+	}
+	ctor, status := DefineClass(env, c.Name, &Caller{Cb: c.construct}, properties)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	// The prototype chains of the instances and of the constructor are linked
+	// to the ones of the base class, as `class extends` does.
+	prototype, status := GetNamedProperty(env, ctor, "prototype")
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	basePrototype, status := GetNamedProperty(env, base, "prototype")
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if status := setPrototypeOf(env, prototype, basePrototype); status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if status := setPrototypeOf(env, ctor, base); status != Status(Statuses.OK) {
+		return nil, status
+	}
+	return ctor, status
+}
+
+// construct is the constructor of the class. The instance is created by the
+// constructor of the base class, so that it is a genuine error with a stack
+// trace, using the prototype of the class that is being constructed.
+func (c *ErrorClass) construct(env Env, info CallbackInfo) Value {
+	ctx := newCallContext(env, info)
+	// Status also reports the failure to read new.target.
+	target := ctx.NewTarget()
+	if ctx.Status() != Status(Statuses.OK) {
+		return nil
+	}
+	if target == nil {
+		return ctx.ThrowTypeError(fmt.Sprintf("Class constructor %s cannot be invoked without 'new'", c.Name))
+	}
+	args := ctx.Args()
+	base, status := c.base(env)
+	if status != Status(Statuses.OK) {
+		return nil
+	}
+	reflectObject, status := globalProperty(env, "Reflect")
+	if status != Status(Statuses.OK) {
+		return nil
+	}
+	construct, status := GetNamedProperty(env, reflectObject, "construct")
+	if status != Status(Statuses.OK) {
+		return nil
+	}
+	list, status := CreateArrayWithLength(env, uint(len(args)))
+	if status != Status(Statuses.OK) {
+		return nil
+	}
+	for i, arg := range args {
+		if status := SetElement(env, list, uint(i), arg); status != Status(Statuses.OK) {
+			return nil
+		}
+	}
+	res, status := CallFunction(env, reflectObject, construct, []Value{base, list, target})
+	if status != Status(Statuses.OK) {
+		return nil
+	}
+	if c.Code != "" {
+		code, status := CreateStringUtf8(env, c.Code)
+		if status != Status(Statuses.OK) {
+			return nil
+		}
+		if status := SetNamedProperty(env, res, "code", code); status != Status(Statuses.OK) {
+			return nil
+		}
+	}
+	// Returning an object from a constructor replaces the one created by new.
+	return res
+}
+
+// newInstance creates an instance of the class for the Go error err, whose
+// chain contains the registered error matched.
+func (c *ErrorClass) newInstance(env Env, msg Value, code Value, matched error) (Value, Status) {
+	ctor, status := c.Constructor(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	res, status := NewInstance(env, ctor, []Value{msg})
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if code != nil {
+		if status := SetNamedProperty(env, res, "code", code); status != Status(Statuses.OK) {
+			return nil, status
+		}
+	}
+	rv := reflect.ValueOf(matched)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return res, status
+	}
+	for _, field := range cachedFields(rv.Type()) {
+		if reservedErrorFields[field.name] {
+			continue
+		}
+		fv, ok := fieldByIndex(rv, field.index, false)
+		if !ok || (field.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		value, err := toJS(env, fv, field.name)
+		if err != nil {
+			// Fields that cannot be converted, like functions, are skipped,
+			// while the failures of N-API calls are reported.
+			var napiErr *Error
+			if errors.As(err, &napiErr) {
+				return nil, napiErr.Status
+			}
+			continue
+		}
+		if status := SetNamedProperty(env, res, field.name, value); status != Status(Statuses.OK) {
+			return nil, status
+		}
+	}
+	return res, status
+}
+
+// reservedErrorFields are the names of the properties set by the Error
+// constructor, that the fields of the Go errors do not overwrite.
+var reservedErrorFields = map[string]bool{
+	"message": true,
+	"stack":   true,
+}
+
+// globalProperty returns the named property of the global object.
+func globalProperty(env Env, name string) (Value, Status) {
+	global, status := GetGlobal(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	return GetNamedProperty(env, global, name)
+}
+
+// setPrototypeOf sets the prototype of the object, with
+// Object.setPrototypeOf.
+func setPrototypeOf(env Env, object Value, prototype Value) Status {
+	objectClass, status := globalProperty(env, "Object")
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	fn, status := GetNamedProperty(env, objectClass, "setPrototypeOf")
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	_, status = CallFunction(env, objectClass, fn, []Value{object, prototype})
+	return status
+}
@@ -0,0 +1,150 @@
+package napi
+
+/*
+#include <node_api.h>
+*/
+import "C"
+import (
+	"fmt"
+)
+
+// Errors
+// Every N-API call returns a Status. CheckStatus turns a failed status into an
+// *Error that carries the status, the message reported by N-API for the last
+// failed call and whether a JavaScript exception is pending:
+//  if err := napi.CheckStatus(env, napi.SetNamedProperty(env, obj, "a", v)); err != nil {
+//  	if errors.Is(err, napi.ErrPendingException) {
+//  		...
+//  	}
+//  }
+// Errors can be matched against the sentinel of their status with errors.Is.
+// Every function returning a Status has a variant, with the E suffix, that
+// returns such an error instead:
+//  if err := napi.SetNamedPropertyE(env, obj, "a", v); err != nil {
+//  	return err
+//  }
+// The variants are generated in errors_api.go by go generate.
+
+//go:generate go run gen_errors.go
+
+// Error describes a failed N-API call.
+type Error struct {
+	// Status is the status returned by the failed call.
+	Status Status
+	// Message is the description of the error reported by N-API, if any.
+	Message string
+	// PendingException reports whether a JavaScript exception was pending
+	// when the error was checked.
+	PendingException bool
+}
+
+func (e *Error) Error() string {
+	msg := "napi: " + e.Status.String()
+	if e.Message != "" {
+		msg = "napi: " + e.Message + " (" + e.Status.String() + ")"
+	}
+	if e.PendingException && e.Status != Status(C.napi_pending_exception) {
+		msg += " with a pending exception"
+	}
+	return msg
+}
+
+// Is reports whether the error has the same status as target, that is usually
+// one of the sentinel errors. Any error with a pending JavaScript exception
+// matches ErrPendingException.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Status == Status(C.napi_pending_exception) && e.PendingException {
+		return true
+	}
+	return t.Status == e.Status && (t.Message == "" || t.Message == e.Message)
+}
+
+// Sentinel errors for every failed status. They are meant to be used with
+// errors.Is.
+var (
+	ErrInvalidArg            = &Error{Status: Status(C.napi_invalid_arg)}
+	ErrObjectExpected        = &Error{Status: Status(C.napi_object_expected)}
+	ErrStringExpected        = &Error{Status: Status(C.napi_string_expected)}
+	ErrNameExpected          = &Error{Status: Status(C.napi_name_expected)}
+	ErrFunctionExpected      = &Error{Status: Status(C.napi_function_expected)}
+	ErrNumberExpected        = &Error{Status: Status(C.napi_number_expected)}
+	ErrBooleanExpected       = &Error{Status: Status(C.napi_boolean_expected)}
+	ErrArrayExpected         = &Error{Status: Status(C.napi_array_expected)}
+	ErrGenericFailure        = &Error{Status: Status(C.napi_generic_failure)}
+	ErrPendingException      = &Error{Status: Status(C.napi_pending_exception)}
+	ErrCancelled             = &Error{Status: Status(C.napi_cancelled)}
+	ErrEscapeCalledTwice     = &Error{Status: Status(C.napi_escape_called_twice)}
+	ErrHandleScopeMismatch   = &Error{Status: Status(C.napi_handle_scope_mismatch)}
+	ErrCallbackScopeMismatch = &Error{Status: Status(C.napi_callback_scope_mismatch)}
+	ErrQueueFull             = &Error{Status: Status(C.napi_queue_full)}
+	ErrClosing               = &Error{Status: Status(C.napi_closing)}
+	ErrBigintExpected        = &Error{Status: Status(C.napi_bigint_expected)}
+	ErrDateExpected          = &Error{Status: Status(C.napi_date_expected)}
+	ErrArrayBufferExpected   = &Error{Status: Status(C.napi_arraybuffer_expected)}
+	ErrDetachableExpected    = &Error{Status: Status(C.napi_detachable_arraybuffer_expected)}
+)
+
+var statusNames = map[Status]string{
+	Status(C.napi_ok):                              "napi_ok",
+	Status(C.napi_invalid_arg):                     "napi_invalid_arg",
+	Status(C.napi_object_expected):                 "napi_object_expected",
+	Status(C.napi_string_expected):                 "napi_string_expected",
+	Status(C.napi_name_expected):                   "napi_name_expected",
+	Status(C.napi_function_expected):               "napi_function_expected",
+	Status(C.napi_number_expected):                 "napi_number_expected",
+	Status(C.napi_boolean_expected):                "napi_boolean_expected",
+	Status(C.napi_array_expected):                  "napi_array_expected",
+	Status(C.napi_generic_failure):                 "napi_generic_failure",
+	Status(C.napi_pending_exception):               "napi_pending_exception",
+	Status(C.napi_cancelled):                       "napi_cancelled",
+	Status(C.napi_escape_called_twice):             "napi_escape_called_twice",
+	Status(C.napi_handle_scope_mismatch):           "napi_handle_scope_mismatch",
+	Status(C.napi_callback_scope_mismatch):         "napi_callback_scope_mismatch",
+	Status(C.napi_queue_full):                      "napi_queue_full",
+	Status(C.napi_closing):                         "napi_closing",
+	Status(C.napi_bigint_expected):                 "napi_bigint_expected",
+	Status(C.napi_date_expected):                   "napi_date_expected",
+	Status(C.napi_arraybuffer_expected):            "napi_arraybuffer_expected",
+	Status(C.napi_detachable_arraybuffer_expected): "napi_detachable_arraybuffer_expected",
+}
+
+// String returns the name of the status, as defined by N-API.
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("napi_status(%d)", int(s))
+}
+
+// errorOf returns nil if status is OK, otherwise an *Error with the status,
+// for the calls that are not made under an environment.
+func errorOf(status Status) error {
+	if status == Status(C.napi_ok) {
+		return nil
+	}
+	return &Error{Status: status}
+}
+
+// CheckStatus function returns nil if status is OK, otherwise an *Error
+// describing the failed N-API call. It must be called right after the call
+// that returned status, before any other N-API call is made on env, as the
+// message of the error is only available until then.
+// [in] env: The environment that the failed call was invoked under.
+// [in] status: The status returned by the call.
+func CheckStatus(env Env, status Status) error {
+	if status == Status(C.napi_ok) {
+		return nil
+	}
+	err := &Error{Status: status}
+	if info, s := GetLastErrorInfo(env); s == Status(C.napi_ok) && info != nil {
+		if Status(info.error_code) == status && info.error_message != nil {
+			err.Message = C.GoString(info.error_message)
+		}
+	}
+	err.PendingException, _ = IsExceptionPending(env)
+	return err
+}
@@ -0,0 +1,1009 @@
+// Code generated by gen_errors.go; DO NOT EDIT.
+
+package napi
+
+import (
+	"math/big"
+	"time"
+	"unsafe"
+)
+
+// CreateBigIntE function is like CreateBigInt, but returns an *Error if the call fails.
+func CreateBigIntE(env Env, value *big.Int) (Value, error) {
+	r0, status := CreateBigInt(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueBigIntE function is like GetValueBigInt, but returns an *Error if the call fails.
+func GetValueBigIntE(env Env, value Value) (*big.Int, error) {
+	r0, status := GetValueBigInt(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// DefineClassForE function is like DefineClassFor, but returns an *Error if the call fails.
+func DefineClassForE(env Env, name string, constructor interface{}) (Value, error) {
+	r0, status := DefineClassFor(env, name, constructor)
+	return r0, CheckStatus(env, status)
+}
+
+// UnwrapObjectE function is like UnwrapObject, but returns an *Error if the call fails.
+func UnwrapObjectE(env Env, value Value) (interface{}, error) {
+	r0, status := UnwrapObject(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// AddEnvCleanupHookE function is like AddEnvCleanupHook, but returns an *Error if the call fails.
+func AddEnvCleanupHookE(env Env, fn CleanupHook) (*CleanupHookHandle, error) {
+	r0, status := AddEnvCleanupHook(env, fn)
+	return r0, CheckStatus(env, status)
+}
+
+// RemoveEnvCleanupHookE function is like RemoveEnvCleanupHook, but returns an *Error if the call fails.
+func RemoveEnvCleanupHookE(env Env, hook *CleanupHookHandle) error {
+	return CheckStatus(env, RemoveEnvCleanupHook(env, hook))
+}
+
+// AddAsyncCleanupHookE function is like AddAsyncCleanupHook, but returns an *Error if the call fails.
+func AddAsyncCleanupHookE(env Env, fn AsyncCleanupHook) (*CleanupHookHandle, error) {
+	r0, status := AddAsyncCleanupHook(env, fn)
+	return r0, CheckStatus(env, status)
+}
+
+// RemoveAsyncCleanupHookE function is like RemoveAsyncCleanupHook, but returns an *Error if the call fails.
+func RemoveAsyncCleanupHookE(hook *CleanupHookHandle) error {
+	return errorOf(RemoveAsyncCleanupHook(hook))
+}
+
+// CreateTimeE function is like CreateTime, but returns an *Error if the call fails.
+func CreateTimeE(env Env, t time.Time) (Value, error) {
+	r0, status := CreateTime(env, t)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueTimeE function is like GetValueTime, but returns an *Error if the call fails.
+func GetValueTimeE(env Env, value Value) (time.Time, error) {
+	r0, status := GetValueTime(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// ExportE function is like Export, but returns an *Error if the call fails.
+func ExportE(env Env, exports Value, name string, fn interface{}) error {
+	return CheckStatus(env, Export(env, exports, name, fn))
+}
+
+// CreateGoBufferE function is like CreateGoBuffer, but returns an *Error if the call fails.
+func CreateGoBufferE(env Env, data []byte, release func([]byte)) (Value, error) {
+	r0, status := CreateGoBuffer(env, data, release)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateGoArrayBufferE function is like CreateGoArrayBuffer, but returns an *Error if the call fails.
+func CreateGoArrayBufferE(env Env, data []byte, release func([]byte)) (Value, error) {
+	r0, status := CreateGoArrayBuffer(env, data, release)
+	return r0, CheckStatus(env, status)
+}
+
+// TransferArrayBufferE function is like TransferArrayBuffer, but returns an *Error if the call fails.
+func TransferArrayBufferE(env Env, value Value) ([]byte, error) {
+	r0, status := TransferArrayBuffer(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// NewFunctionE function is like NewFunction, but returns an *Error if the call fails.
+func NewFunctionE(env Env, name string, length int, cb FunctionCallback) (Function, error) {
+	r0, status := NewFunction(env, name, length, cb)
+	return r0, CheckStatus(env, status)
+}
+
+// ThrowGoErrorE function is like ThrowGoError, but returns an *Error if the call fails.
+func ThrowGoErrorE(env Env, err error) error {
+	return CheckStatus(env, ThrowGoError(env, err))
+}
+
+// CreateGoErrorE function is like CreateGoError, but returns an *Error if the call fails.
+func CreateGoErrorE(env Env, err error) (Value, error) {
+	r0, status := CreateGoError(env, err)
+	return r0, CheckStatus(env, status)
+}
+
+// AsObjectE function is like AsObject, but returns an *Error if the call fails.
+func AsObjectE(env Env, value Value) (Object, error) {
+	r0, status := AsObject(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// NewObjectE function is like NewObject, but returns an *Error if the call fails.
+func NewObjectE(env Env) (Object, error) {
+	r0, status := NewObject(env)
+	return r0, CheckStatus(env, status)
+}
+
+// AsArrayE function is like AsArray, but returns an *Error if the call fails.
+func AsArrayE(env Env, value Value) (Array, error) {
+	r0, status := AsArray(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// NewArrayE function is like NewArray, but returns an *Error if the call fails.
+func NewArrayE(env Env, values ...Value) (Array, error) {
+	r0, status := NewArray(env, values...)
+	return r0, CheckStatus(env, status)
+}
+
+// AsFunctionE function is like AsFunction, but returns an *Error if the call fails.
+func AsFunctionE(env Env, value Value) (Function, error) {
+	r0, status := AsFunction(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// SetInstanceDataE function is like SetInstanceData, but returns an *Error if the call fails.
+func SetInstanceDataE(env Env, data interface{}, finalizer EnvFinalize) error {
+	return CheckStatus(env, SetInstanceData(env, data, finalizer))
+}
+
+// GetInstanceDataE function is like GetInstanceData, but returns an *Error if the call fails.
+func GetInstanceDataE(env Env) (interface{}, error) {
+	r0, status := GetInstanceData(env)
+	return r0, CheckStatus(env, status)
+}
+
+// GetLastErrorInfoE function is like GetLastErrorInfo, but returns an *Error if the call fails.
+func GetLastErrorInfoE(env Env) (ExtendedErrorInfo, error) {
+	r0, status := GetLastErrorInfo(env)
+	return r0, CheckStatus(env, status)
+}
+
+// ThrowE function is like Throw, but returns an *Error if the call fails.
+func ThrowE(env Env, value Value) error {
+	return CheckStatus(env, Throw(env, value))
+}
+
+// ThrowErrorE function is like ThrowError, but returns an *Error if the call fails.
+func ThrowErrorE(env Env, msg string, code string) error {
+	return CheckStatus(env, ThrowError(env, msg, code))
+}
+
+// ThrowTypeErrorE function is like ThrowTypeError, but returns an *Error if the call fails.
+func ThrowTypeErrorE(env Env, msg string, code string) error {
+	return CheckStatus(env, ThrowTypeError(env, msg, code))
+}
+
+// ThrowRangErrorE function is like ThrowRangError, but returns an *Error if the call fails.
+func ThrowRangErrorE(env Env, msg string, code string) error {
+	return CheckStatus(env, ThrowRangError(env, msg, code))
+}
+
+// IsErrorE function is like IsError, but returns an *Error if the call fails.
+func IsErrorE(env Env, value Value) (bool, error) {
+	r0, status := IsError(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateErrorE function is like CreateError, but returns an *Error if the call fails.
+func CreateErrorE(env Env, msg Value, code Value) (Value, error) {
+	r0, status := CreateError(env, msg, code)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateTypeErrorE function is like CreateTypeError, but returns an *Error if the call fails.
+func CreateTypeErrorE(env Env, code Value, msg Value) (Value, error) {
+	r0, status := CreateTypeError(env, code, msg)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateRangeErrorE function is like CreateRangeError, but returns an *Error if the call fails.
+func CreateRangeErrorE(env Env, code Value, msg Value) (Value, error) {
+	r0, status := CreateRangeError(env, code, msg)
+	return r0, CheckStatus(env, status)
+}
+
+// GetAndClearLastExceptionE function is like GetAndClearLastException, but returns an *Error if the call fails.
+func GetAndClearLastExceptionE(env Env) (Value, error) {
+	r0, status := GetAndClearLastException(env)
+	return r0, CheckStatus(env, status)
+}
+
+// IsExceptionPendingE function is like IsExceptionPending, but returns an *Error if the call fails.
+func IsExceptionPendingE(env Env) (bool, error) {
+	r0, status := IsExceptionPending(env)
+	return r0, CheckStatus(env, status)
+}
+
+// FatalExceptionE function is like FatalException, but returns an *Error if the call fails.
+func FatalExceptionE(env Env, value Value) error {
+	return CheckStatus(env, FatalException(env, value))
+}
+
+// OnpenHandleScopeE function is like OnpenHandleScope, but returns an *Error if the call fails.
+func OnpenHandleScopeE(env Env) (HandleScope, error) {
+	r0, status := OnpenHandleScope(env)
+	return r0, CheckStatus(env, status)
+}
+
+// CloseHandleScopeE function is like CloseHandleScope, but returns an *Error if the call fails.
+func CloseHandleScopeE(env Env, scope HandleScope) error {
+	return CheckStatus(env, CloseHandleScope(env, scope))
+}
+
+// OnpenEscapableHandleScopeE function is like OnpenEscapableHandleScope, but returns an *Error if the call fails.
+func OnpenEscapableHandleScopeE(env Env) (EscapableHandleScope, error) {
+	r0, status := OnpenEscapableHandleScope(env)
+	return r0, CheckStatus(env, status)
+}
+
+// CloseEscapableHandleScopeE function is like CloseEscapableHandleScope, but returns an *Error if the call fails.
+func CloseEscapableHandleScopeE(env Env, scope EscapableHandleScope) error {
+	return CheckStatus(env, CloseEscapableHandleScope(env, scope))
+}
+
+// EscapeHandleE function is like EscapeHandle, but returns an *Error if the call fails.
+func EscapeHandleE(env Env, scope EscapableHandleScope, escapee Value) (Value, error) {
+	r0, status := EscapeHandle(env, scope, escapee)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateReferenceE function is like CreateReference, but returns an *Error if the call fails.
+func CreateReferenceE(env Env, value Value, refCount uint) (Ref, error) {
+	r0, status := CreateReference(env, value, refCount)
+	return r0, CheckStatus(env, status)
+}
+
+// DeleteReferenceE function is like DeleteReference, but returns an *Error if the call fails.
+func DeleteReferenceE(env Env, ref Ref) error {
+	return CheckStatus(env, DeleteReference(env, ref))
+}
+
+// ReferenceRefE function is like ReferenceRef, but returns an *Error if the call fails.
+func ReferenceRefE(env Env, ref Ref) (uint, error) {
+	r0, status := ReferenceRef(env, ref)
+	return r0, CheckStatus(env, status)
+}
+
+// ReferenceUnrefE function is like ReferenceUnref, but returns an *Error if the call fails.
+func ReferenceUnrefE(env Env, ref Ref) (uint, error) {
+	r0, status := ReferenceUnref(env, ref)
+	return r0, CheckStatus(env, status)
+}
+
+// GetReferenceValueE function is like GetReferenceValue, but returns an *Error if the call fails.
+func GetReferenceValueE(env Env, ref Ref) (Value, error) {
+	r0, status := GetReferenceValue(env, ref)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateArrayE function is like CreateArray, but returns an *Error if the call fails.
+func CreateArrayE(env Env) (Value, error) {
+	r0, status := CreateArray(env)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateArrayWithLengthE function is like CreateArrayWithLength, but returns an *Error if the call fails.
+func CreateArrayWithLengthE(env Env, length uint) (Value, error) {
+	r0, status := CreateArrayWithLength(env, length)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateArrayBufferE function is like CreateArrayBuffer, but returns an *Error if the call fails.
+func CreateArrayBufferE(env Env, length uint) (Value, unsafe.Pointer, error) {
+	r0, r1, status := CreateArrayBuffer(env, length)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// CreateBufferE function is like CreateBuffer, but returns an *Error if the call fails.
+func CreateBufferE(env Env, length uint) (Value, unsafe.Pointer, error) {
+	r0, r1, status := CreateBuffer(env, length)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// CreateBufferCopyE function is like CreateBufferCopy, but returns an *Error if the call fails.
+func CreateBufferCopyE(env Env, length uint, raw unsafe.Pointer) (Value, unsafe.Pointer, error) {
+	r0, r1, status := CreateBufferCopy(env, length, raw)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// CreateExternalE function is like CreateExternal, but returns an *Error if the call fails.
+func CreateExternalE(env Env, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, error) {
+	r0, status := CreateExternal(env, raw, finalizer, hint)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateExternalArrayBufferE function is like CreateExternalArrayBuffer, but returns an *Error if the call fails.
+func CreateExternalArrayBufferE(env Env, length uint, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, error) {
+	r0, status := CreateExternalArrayBuffer(env, length, raw, finalizer, hint)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateExternalBufferE function is like CreateExternalBuffer, but returns an *Error if the call fails.
+func CreateExternalBufferE(env Env, length uint, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, error) {
+	r0, status := CreateExternalBuffer(env, length, raw, finalizer, hint)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateObjectE function is like CreateObject, but returns an *Error if the call fails.
+func CreateObjectE(env Env) (Value, error) {
+	r0, status := CreateObject(env)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateSymbolE function is like CreateSymbol, but returns an *Error if the call fails.
+func CreateSymbolE(env Env, value Value) (Value, error) {
+	r0, status := CreateSymbol(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateTypedArrayE function is like CreateTypedArray, but returns an *Error if the call fails.
+func CreateTypedArrayE(env Env, arrayType TypedArrayType, lenght uint, value Value, offset uint) (Value, error) {
+	r0, status := CreateTypedArray(env, arrayType, lenght, value, offset)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateDataviewE function is like CreateDataview, but returns an *Error if the call fails.
+func CreateDataviewE(env Env, length uint, offset uint, value Value) (Value, error) {
+	r0, status := CreateDataview(env, length, offset, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateDateE function is like CreateDate, but returns an *Error if the call fails.
+func CreateDateE(env Env, time float64) (Value, error) {
+	r0, status := CreateDate(env, time)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateInt32E function is like CreateInt32, but returns an *Error if the call fails.
+func CreateInt32E(env Env, value int32) (Value, error) {
+	r0, status := CreateInt32(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateUInt32E function is like CreateUInt32, but returns an *Error if the call fails.
+func CreateUInt32E(env Env, value uint32) (Value, error) {
+	r0, status := CreateUInt32(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateInt64E function is like CreateInt64, but returns an *Error if the call fails.
+func CreateInt64E(env Env, value int64) (Value, error) {
+	r0, status := CreateInt64(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateDoubleE function is like CreateDouble, but returns an *Error if the call fails.
+func CreateDoubleE(env Env, value float64) (Value, error) {
+	r0, status := CreateDouble(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateBigintInt64E function is like CreateBigintInt64, but returns an *Error if the call fails.
+func CreateBigintInt64E(env Env, value int64) (Value, error) {
+	r0, status := CreateBigintInt64(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateBigintUInt64E function is like CreateBigintUInt64, but returns an *Error if the call fails.
+func CreateBigintUInt64E(env Env, value uint64) (Value, error) {
+	r0, status := CreateBigintUInt64(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateBigintWordsE function is like CreateBigintWords, but returns an *Error if the call fails.
+func CreateBigintWordsE(env Env, sign int, words []uint64) (Value, error) {
+	r0, status := CreateBigintWords(env, sign, words)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateStringLatin1E function is like CreateStringLatin1, but returns an *Error if the call fails.
+func CreateStringLatin1E(env Env, str string) (Value, error) {
+	r0, status := CreateStringLatin1(env, str)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateStringUtf16E function is like CreateStringUtf16, but returns an *Error if the call fails.
+func CreateStringUtf16E(env Env, str string) (Value, error) {
+	r0, status := CreateStringUtf16(env, str)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateStringUtf8E function is like CreateStringUtf8, but returns an *Error if the call fails.
+func CreateStringUtf8E(env Env, str string) (Value, error) {
+	r0, status := CreateStringUtf8(env, str)
+	return r0, CheckStatus(env, status)
+}
+
+// GetArrayLengthE function is like GetArrayLength, but returns an *Error if the call fails.
+func GetArrayLengthE(env Env, value Value) (uint32, error) {
+	r0, status := GetArrayLength(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetArrayBufferInfoE function is like GetArrayBufferInfo, but returns an *Error if the call fails.
+func GetArrayBufferInfoE(env Env, value Value) (unsafe.Pointer, uint, error) {
+	r0, r1, status := GetArrayBufferInfo(env, value)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// GetBufferInfoE function is like GetBufferInfo, but returns an *Error if the call fails.
+func GetBufferInfoE(env Env, value Value) (unsafe.Pointer, uint, error) {
+	r0, r1, status := GetBufferInfo(env, value)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// DetachArrayBufferE function is like DetachArrayBuffer, but returns an *Error if the call fails.
+func DetachArrayBufferE(env Env, value Value) error {
+	return CheckStatus(env, DetachArrayBuffer(env, value))
+}
+
+// IsDetachedArrayBufferE function is like IsDetachedArrayBuffer, but returns an *Error if the call fails.
+func IsDetachedArrayBufferE(env Env, value Value) (bool, error) {
+	r0, status := IsDetachedArrayBuffer(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetPrototypeE function is like GetPrototype, but returns an *Error if the call fails.
+func GetPrototypeE(env Env, object Value) (Value, error) {
+	r0, status := GetPrototype(env, object)
+	return r0, CheckStatus(env, status)
+}
+
+// GetTypedArrayInfoE function is like GetTypedArrayInfo, but returns an *Error if the call fails.
+func GetTypedArrayInfoE(env Env, value Value) (Value, TypedArrayType, uint, unsafe.Pointer, uint, error) {
+	r0, r1, r2, r3, r4, status := GetTypedArrayInfo(env, value)
+	return r0, r1, r2, r3, r4, CheckStatus(env, status)
+}
+
+// GetDataviewInfoE function is like GetDataviewInfo, but returns an *Error if the call fails.
+func GetDataviewInfoE(env Env, value Value) (Value, uint, uint, error) {
+	r0, r1, r2, status := GetDataviewInfo(env, value)
+	return r0, r1, r2, CheckStatus(env, status)
+}
+
+// GetDateValueE function is like GetDateValue, but returns an *Error if the call fails.
+func GetDateValueE(env Env, value Value) (float64, error) {
+	r0, status := GetDateValue(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueBoolE function is like GetValueBool, but returns an *Error if the call fails.
+func GetValueBoolE(env Env, value Value) (bool, error) {
+	r0, status := GetValueBool(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueDoubleE function is like GetValueDouble, but returns an *Error if the call fails.
+func GetValueDoubleE(env Env, value Value) (float64, error) {
+	r0, status := GetValueDouble(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueBigintInt64E function is like GetValueBigintInt64, but returns an *Error if the call fails.
+func GetValueBigintInt64E(env Env, value Value) (int64, bool, error) {
+	r0, r1, status := GetValueBigintInt64(env, value)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// GetValueBigintUInt64E function is like GetValueBigintUInt64, but returns an *Error if the call fails.
+func GetValueBigintUInt64E(env Env, value Value) (uint64, bool, error) {
+	r0, r1, status := GetValueBigintUInt64(env, value)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// GetValueBigintWordsE function is like GetValueBigintWords, but returns an *Error if the call fails.
+func GetValueBigintWordsE(env Env, value Value) ([]uint64, int, error) {
+	r0, r1, status := GetValueBigintWords(env, value)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// GetValueExternalE function is like GetValueExternal, but returns an *Error if the call fails.
+func GetValueExternalE(env Env, value Value) (unsafe.Pointer, error) {
+	r0, status := GetValueExternal(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueInt32E function is like GetValueInt32, but returns an *Error if the call fails.
+func GetValueInt32E(env Env, value Value) (int32, error) {
+	r0, status := GetValueInt32(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueInt64E function is like GetValueInt64, but returns an *Error if the call fails.
+func GetValueInt64E(env Env, value Value) (int64, error) {
+	r0, status := GetValueInt64(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueStringLatin1E function is like GetValueStringLatin1, but returns an *Error if the call fails.
+func GetValueStringLatin1E(env Env, value Value) (string, error) {
+	r0, status := GetValueStringLatin1(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueStringUtf8E function is like GetValueStringUtf8, but returns an *Error if the call fails.
+func GetValueStringUtf8E(env Env, value Value) (string, error) {
+	r0, status := GetValueStringUtf8(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueStringUtf16E function is like GetValueStringUtf16, but returns an *Error if the call fails.
+func GetValueStringUtf16E(env Env, value Value) (string, error) {
+	r0, status := GetValueStringUtf16(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetValueUint32E function is like GetValueUint32, but returns an *Error if the call fails.
+func GetValueUint32E(env Env, value Value) (uint32, error) {
+	r0, status := GetValueUint32(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetBooleanE function is like GetBoolean, but returns an *Error if the call fails.
+func GetBooleanE(env Env, value bool) (Value, error) {
+	r0, status := GetBoolean(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetGlobalE function is like GetGlobal, but returns an *Error if the call fails.
+func GetGlobalE(env Env) (Value, error) {
+	r0, status := GetGlobal(env)
+	return r0, CheckStatus(env, status)
+}
+
+// GetNullE function is like GetNull, but returns an *Error if the call fails.
+func GetNullE(env Env) (Value, error) {
+	r0, status := GetNull(env)
+	return r0, CheckStatus(env, status)
+}
+
+// GetUndefinedE function is like GetUndefined, but returns an *Error if the call fails.
+func GetUndefinedE(env Env) (Value, error) {
+	r0, status := GetUndefined(env)
+	return r0, CheckStatus(env, status)
+}
+
+// CoerceToBoolE function is like CoerceToBool, but returns an *Error if the call fails.
+func CoerceToBoolE(env Env, value Value) (Value, error) {
+	r0, status := CoerceToBool(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CoerceToNumberE function is like CoerceToNumber, but returns an *Error if the call fails.
+func CoerceToNumberE(env Env, value Value) (Value, error) {
+	r0, status := CoerceToNumber(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CoerceToObjectE function is like CoerceToObject, but returns an *Error if the call fails.
+func CoerceToObjectE(env Env, value Value) (Value, error) {
+	r0, status := CoerceToObject(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CoerceToStringE function is like CoerceToString, but returns an *Error if the call fails.
+func CoerceToStringE(env Env, value Value) (Value, error) {
+	r0, status := CoerceToString(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// TypeOfE function is like TypeOf, but returns an *Error if the call fails.
+func TypeOfE(env Env, value Value) (ValueType, error) {
+	r0, status := TypeOf(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// InstanceOfE function is like InstanceOf, but returns an *Error if the call fails.
+func InstanceOfE(env Env, object Value, constructor Value) (bool, error) {
+	r0, status := InstanceOf(env, object, constructor)
+	return r0, CheckStatus(env, status)
+}
+
+// IsArrayE function is like IsArray, but returns an *Error if the call fails.
+func IsArrayE(env Env, value Value) (bool, error) {
+	r0, status := IsArray(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// IsArrayBufferE function is like IsArrayBuffer, but returns an *Error if the call fails.
+func IsArrayBufferE(env Env, value Value) (bool, error) {
+	r0, status := IsArrayBuffer(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// IsBufferE function is like IsBuffer, but returns an *Error if the call fails.
+func IsBufferE(env Env, value Value) (bool, error) {
+	r0, status := IsBuffer(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// IsTypedArrayE function is like IsTypedArray, but returns an *Error if the call fails.
+func IsTypedArrayE(env Env, value Value) (bool, error) {
+	r0, status := IsTypedArray(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// IsDataviewE function is like IsDataview, but returns an *Error if the call fails.
+func IsDataviewE(env Env, value Value) (bool, error) {
+	r0, status := IsDataview(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// IsDateE function is like IsDate, but returns an *Error if the call fails.
+func IsDateE(env Env, value Value) (bool, error) {
+	r0, status := IsDate(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// StrictEqualsE function is like StrictEquals, but returns an *Error if the call fails.
+func StrictEqualsE(env Env, lhs Value, rhs Value) (bool, error) {
+	r0, status := StrictEquals(env, lhs, rhs)
+	return r0, CheckStatus(env, status)
+}
+
+// GetPropertyNamesE function is like GetPropertyNames, but returns an *Error if the call fails.
+func GetPropertyNamesE(env Env, object Value) (Value, error) {
+	r0, status := GetPropertyNames(env, object)
+	return r0, CheckStatus(env, status)
+}
+
+// GetAllPropertyNamesE function is like GetAllPropertyNames, but returns an *Error if the call fails.
+func GetAllPropertyNamesE(env Env, object Value, mode KeyCollectionMode, filter KeyFilter, conversion KeyConversion) (Value, error) {
+	r0, status := GetAllPropertyNames(env, object, mode, filter, conversion)
+	return r0, CheckStatus(env, status)
+}
+
+// ObjectFreezeE function is like ObjectFreeze, but returns an *Error if the call fails.
+func ObjectFreezeE(env Env, object Value) error {
+	return CheckStatus(env, ObjectFreeze(env, object))
+}
+
+// ObjectSealE function is like ObjectSeal, but returns an *Error if the call fails.
+func ObjectSealE(env Env, object Value) error {
+	return CheckStatus(env, ObjectSeal(env, object))
+}
+
+// SetPropertyE function is like SetProperty, but returns an *Error if the call fails.
+func SetPropertyE(env Env, object Value, key Value, value Value) error {
+	return CheckStatus(env, SetProperty(env, object, key, value))
+}
+
+// GetPropertyE function is like GetProperty, but returns an *Error if the call fails.
+func GetPropertyE(env Env, object Value, key Value) (Value, error) {
+	r0, status := GetProperty(env, object, key)
+	return r0, CheckStatus(env, status)
+}
+
+// HasPropertyE function is like HasProperty, but returns an *Error if the call fails.
+func HasPropertyE(env Env, object Value, key Value) (bool, error) {
+	r0, status := HasProperty(env, object, key)
+	return r0, CheckStatus(env, status)
+}
+
+// DeletePropertyE function is like DeleteProperty, but returns an *Error if the call fails.
+func DeletePropertyE(env Env, object Value, key Value) (bool, error) {
+	r0, status := DeleteProperty(env, object, key)
+	return r0, CheckStatus(env, status)
+}
+
+// HasOwnPropertyE function is like HasOwnProperty, but returns an *Error if the call fails.
+func HasOwnPropertyE(env Env, object Value, key Value) (bool, error) {
+	r0, status := HasOwnProperty(env, object, key)
+	return r0, CheckStatus(env, status)
+}
+
+// SetNamedPropertyE function is like SetNamedProperty, but returns an *Error if the call fails.
+func SetNamedPropertyE(env Env, object Value, key string, value Value) error {
+	return CheckStatus(env, SetNamedProperty(env, object, key, value))
+}
+
+// GetNamedPropertyE function is like GetNamedProperty, but returns an *Error if the call fails.
+func GetNamedPropertyE(env Env, object Value, key string) (Value, error) {
+	r0, status := GetNamedProperty(env, object, key)
+	return r0, CheckStatus(env, status)
+}
+
+// HasNamedPropertyE function is like HasNamedProperty, but returns an *Error if the call fails.
+func HasNamedPropertyE(env Env, object Value, key string) (bool, error) {
+	r0, status := HasNamedProperty(env, object, key)
+	return r0, CheckStatus(env, status)
+}
+
+// SetElementE function is like SetElement, but returns an *Error if the call fails.
+func SetElementE(env Env, object Value, index uint, value Value) error {
+	return CheckStatus(env, SetElement(env, object, index, value))
+}
+
+// GetElementE function is like GetElement, but returns an *Error if the call fails.
+func GetElementE(env Env, object Value, index uint) (Value, error) {
+	r0, status := GetElement(env, object, index)
+	return r0, CheckStatus(env, status)
+}
+
+// HasElementE function is like HasElement, but returns an *Error if the call fails.
+func HasElementE(env Env, object Value, index uint) (bool, error) {
+	r0, status := HasElement(env, object, index)
+	return r0, CheckStatus(env, status)
+}
+
+// DeleteElementE function is like DeleteElement, but returns an *Error if the call fails.
+func DeleteElementE(env Env, object Value, index uint) (bool, error) {
+	r0, status := DeleteElement(env, object, index)
+	return r0, CheckStatus(env, status)
+}
+
+// DefinePropertiesE function is like DefineProperties, but returns an *Error if the call fails.
+func DefinePropertiesE(env Env, value Value, properties []Property) error {
+	return CheckStatus(env, DefineProperties(env, value, properties))
+}
+
+// CallFunctionE function is like CallFunction, but returns an *Error if the call fails.
+func CallFunctionE(env Env, receiver Value, function Value, arguments []Value) (Value, error) {
+	r0, status := CallFunction(env, receiver, function, arguments)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateFunctionE function is like CreateFunction, but returns an *Error if the call fails.
+func CreateFunctionE(env Env, name string, cb CCallback) (Value, error) {
+	r0, status := CreateFunction(env, name, cb)
+	return r0, CheckStatus(env, status)
+}
+
+// GetCbInfoE function is like GetCbInfo, but returns an *Error if the call fails.
+func GetCbInfoE(env Env, cbinfo CallbackInfo) ([]Value, Value, unsafe.Pointer, error) {
+	r0, r1, r2, status := GetCbInfo(env, cbinfo)
+	return r0, r1, r2, CheckStatus(env, status)
+}
+
+// GetNewTargetE function is like GetNewTarget, but returns an *Error if the call fails.
+func GetNewTargetE(env Env, cbinfo CallbackInfo) (Value, error) {
+	r0, status := GetNewTarget(env, cbinfo)
+	return r0, CheckStatus(env, status)
+}
+
+// NewInstanceE function is like NewInstance, but returns an *Error if the call fails.
+func NewInstanceE(env Env, ctor Value, arguments []Value) (Value, error) {
+	r0, status := NewInstance(env, ctor, arguments)
+	return r0, CheckStatus(env, status)
+}
+
+// DefineClassE function is like DefineClass, but returns an *Error if the call fails.
+func DefineClassE(env Env, name string, ctor *Caller, properties []Property) (Value, error) {
+	r0, status := DefineClass(env, name, ctor, properties)
+	return r0, CheckStatus(env, status)
+}
+
+// WrapE function is like Wrap, but returns an *Error if the call fails.
+func WrapE(env Env, value Value, native unsafe.Pointer) (Ref, error) {
+	r0, status := Wrap(env, value, native)
+	return r0, CheckStatus(env, status)
+}
+
+// UnwrapE function is like Unwrap, but returns an *Error if the call fails.
+func UnwrapE(env Env, value Value) (unsafe.Pointer, error) {
+	r0, status := Unwrap(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// RemoveWrapE function is like RemoveWrap, but returns an *Error if the call fails.
+func RemoveWrapE(env Env, value Value) (unsafe.Pointer, error) {
+	r0, status := RemoveWrap(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// AddFinalizerE function is like AddFinalizer, but returns an *Error if the call fails.
+func AddFinalizerE(env Env, obj Value, native unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Ref, error) {
+	r0, status := AddFinalizer(env, obj, native, finalizer, hint)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateAsyncWorkE function is like CreateAsyncWork, but returns an *Error if the call fails.
+func CreateAsyncWorkE(env Env, resource Value, name Value, execute *AsyncExecuteCaller, complete *AsyncCompleteCaller, data unsafe.Pointer) (AsyncWork, error) {
+	r0, status := CreateAsyncWork(env, resource, name, execute, complete, data)
+	return r0, CheckStatus(env, status)
+}
+
+// DeleteAsyncWorkE function is like DeleteAsyncWork, but returns an *Error if the call fails.
+func DeleteAsyncWorkE(env Env, work AsyncWork) error {
+	return CheckStatus(env, DeleteAsyncWork(env, work))
+}
+
+// QueueAsyncWorkE function is like QueueAsyncWork, but returns an *Error if the call fails.
+func QueueAsyncWorkE(env Env, work AsyncWork) error {
+	return CheckStatus(env, QueueAsyncWork(env, work))
+}
+
+// CancelAsyncWorkE function is like CancelAsyncWork, but returns an *Error if the call fails.
+func CancelAsyncWorkE(env Env, work AsyncWork) error {
+	return CheckStatus(env, CancelAsyncWork(env, work))
+}
+
+// AsyncInitE function is like AsyncInit, but returns an *Error if the call fails.
+func AsyncInitE(env Env, resource Value, name Value) (AsyncContext, error) {
+	r0, status := AsyncInit(env, resource, name)
+	return r0, CheckStatus(env, status)
+}
+
+// AsyncDestroyE function is like AsyncDestroy, but returns an *Error if the call fails.
+func AsyncDestroyE(env Env, ctx AsyncContext) error {
+	return CheckStatus(env, AsyncDestroy(env, ctx))
+}
+
+// MakeCallbackE function is like MakeCallback, but returns an *Error if the call fails.
+func MakeCallbackE(env Env, ctx AsyncContext, recv Value, fn Value, args []Value) (Value, error) {
+	r0, status := MakeCallback(env, ctx, recv, fn, args)
+	return r0, CheckStatus(env, status)
+}
+
+// OpenCallbackScopeE function is like OpenCallbackScope, but returns an *Error if the call fails.
+func OpenCallbackScopeE(env Env, resource Value, ctx AsyncContext) (CallbackScope, error) {
+	r0, status := OpenCallbackScope(env, resource, ctx)
+	return r0, CheckStatus(env, status)
+}
+
+// CloseCallbackScopeE function is like CloseCallbackScope, but returns an *Error if the call fails.
+func CloseCallbackScopeE(env Env, scope CallbackScope) error {
+	return CheckStatus(env, CloseCallbackScope(env, scope))
+}
+
+// GetNodeVersionE function is like GetNodeVersion, but returns an *Error if the call fails.
+func GetNodeVersionE(env Env) (NodeVersion, error) {
+	r0, status := GetNodeVersion(env)
+	return r0, CheckStatus(env, status)
+}
+
+// GetVersionE function is like GetVersion, but returns an *Error if the call fails.
+func GetVersionE(env Env) (uint32, error) {
+	r0, status := GetVersion(env)
+	return r0, CheckStatus(env, status)
+}
+
+// AdjustExternalMemoryE function is like AdjustExternalMemory, but returns an *Error if the call fails.
+func AdjustExternalMemoryE(env Env, changeInBytes int64) (int64, error) {
+	r0, status := AdjustExternalMemory(env, changeInBytes)
+	return r0, CheckStatus(env, status)
+}
+
+// CreatePromiseE function is like CreatePromise, but returns an *Error if the call fails.
+func CreatePromiseE(env Env) (Value, Deferred, error) {
+	r0, r1, status := CreatePromise(env)
+	return r0, r1, CheckStatus(env, status)
+}
+
+// ResolveDeferredE function is like ResolveDeferred, but returns an *Error if the call fails.
+func ResolveDeferredE(env Env, deferred Deferred, resolution Value) error {
+	return CheckStatus(env, ResolveDeferred(env, deferred, resolution))
+}
+
+// RejectDeferredE function is like RejectDeferred, but returns an *Error if the call fails.
+func RejectDeferredE(env Env, deferred Deferred, rejection Value) error {
+	return CheckStatus(env, RejectDeferred(env, deferred, rejection))
+}
+
+// IsPromiseE function is like IsPromise, but returns an *Error if the call fails.
+func IsPromiseE(env Env, value Value) (bool, error) {
+	r0, status := IsPromise(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// RunScriptE function is like RunScript, but returns an *Error if the call fails.
+func RunScriptE(env Env, script Value) (Value, error) {
+	r0, status := RunScript(env, script)
+	return r0, CheckStatus(env, status)
+}
+
+// GetUvEventLoopE function is like GetUvEventLoop, but returns an *Error if the call fails.
+func GetUvEventLoopE(env Env) (UVLoop, error) {
+	r0, status := GetUvEventLoop(env)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateThreadsafeFunctionE function is like CreateThreadsafeFunction, but returns an *Error if the call fails.
+func CreateThreadsafeFunctionE(env Env, fn Value, resource Value, name Value, maxQueueSize uint, initialThreadCount uint, data unsafe.Pointer, finalizer *FinalizeCaller, ctx unsafe.Pointer, tsfn *ThreadsafeFunctionsCaller) (ThreadsafeFunction, error) {
+	r0, status := CreateThreadsafeFunction(env, fn, resource, name, maxQueueSize, initialThreadCount, data, finalizer, ctx, tsfn)
+	return r0, CheckStatus(env, status)
+}
+
+// GetThreadsafeFunctionContextE function is like GetThreadsafeFunctionContext, but returns an *Error if the call fails.
+func GetThreadsafeFunctionContextE(fn ThreadsafeFunction) (unsafe.Pointer, error) {
+	r0, status := GetThreadsafeFunctionContext(fn)
+	return r0, errorOf(status)
+}
+
+// CallThreadsafeFunctionE function is like CallThreadsafeFunction, but returns an *Error if the call fails.
+func CallThreadsafeFunctionE(fn ThreadsafeFunction, data unsafe.Pointer, mode ThreadsafeFunctionCallMode) error {
+	return errorOf(CallThreadsafeFunction(fn, data, mode))
+}
+
+// AcquireThreadsafeFunctionE function is like AcquireThreadsafeFunction, but returns an *Error if the call fails.
+func AcquireThreadsafeFunctionE(fn ThreadsafeFunction) error {
+	return errorOf(AcquireThreadsafeFunction(fn))
+}
+
+// ReleaseThreadsafeFunctionE function is like ReleaseThreadsafeFunction, but returns an *Error if the call fails.
+func ReleaseThreadsafeFunctionE(fn ThreadsafeFunction, mode TheradsafeFunctionReleaseMode) error {
+	return errorOf(ReleaseThreadsafeFunction(fn, mode))
+}
+
+// RefThreadsafeFunctionE function is like RefThreadsafeFunction, but returns an *Error if the call fails.
+func RefThreadsafeFunctionE(env Env, fn ThreadsafeFunction) error {
+	return CheckStatus(env, RefThreadsafeFunction(env, fn))
+}
+
+// UnrefThreadsafeFunctionE function is like UnrefThreadsafeFunction, but returns an *Error if the call fails.
+func UnrefThreadsafeFunctionE(env Env, fn ThreadsafeFunction) error {
+	return CheckStatus(env, UnrefThreadsafeFunction(env, fn))
+}
+
+// GetInt8ArrayE function is like GetInt8Array, but returns an *Error if the call fails.
+func GetInt8ArrayE(env Env, value Value) ([]int8, error) {
+	r0, status := GetInt8Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetUInt8ArrayE function is like GetUInt8Array, but returns an *Error if the call fails.
+func GetUInt8ArrayE(env Env, value Value) ([]uint8, error) {
+	r0, status := GetUInt8Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetUInt8ClampedArrayE function is like GetUInt8ClampedArray, but returns an *Error if the call fails.
+func GetUInt8ClampedArrayE(env Env, value Value) ([]uint8, error) {
+	r0, status := GetUInt8ClampedArray(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetInt16ArrayE function is like GetInt16Array, but returns an *Error if the call fails.
+func GetInt16ArrayE(env Env, value Value) ([]int16, error) {
+	r0, status := GetInt16Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetUInt16ArrayE function is like GetUInt16Array, but returns an *Error if the call fails.
+func GetUInt16ArrayE(env Env, value Value) ([]uint16, error) {
+	r0, status := GetUInt16Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetInt32ArrayE function is like GetInt32Array, but returns an *Error if the call fails.
+func GetInt32ArrayE(env Env, value Value) ([]int32, error) {
+	r0, status := GetInt32Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetUInt32ArrayE function is like GetUInt32Array, but returns an *Error if the call fails.
+func GetUInt32ArrayE(env Env, value Value) ([]uint32, error) {
+	r0, status := GetUInt32Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetFloat32ArrayE function is like GetFloat32Array, but returns an *Error if the call fails.
+func GetFloat32ArrayE(env Env, value Value) ([]float32, error) {
+	r0, status := GetFloat32Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetFloat64ArrayE function is like GetFloat64Array, but returns an *Error if the call fails.
+func GetFloat64ArrayE(env Env, value Value) ([]float64, error) {
+	r0, status := GetFloat64Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetBigInt64ArrayE function is like GetBigInt64Array, but returns an *Error if the call fails.
+func GetBigInt64ArrayE(env Env, value Value) ([]int64, error) {
+	r0, status := GetBigInt64Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// GetBigUInt64ArrayE function is like GetBigUInt64Array, but returns an *Error if the call fails.
+func GetBigUInt64ArrayE(env Env, value Value) ([]uint64, error) {
+	r0, status := GetBigUInt64Array(env, value)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateTypedArrayFromSliceE function is like CreateTypedArrayFromSlice, but returns an *Error if the call fails.
+func CreateTypedArrayFromSliceE(env Env, slice interface{}) (Value, error) {
+	r0, status := CreateTypedArrayFromSlice(env, slice)
+	return r0, CheckStatus(env, status)
+}
+
+// CreateExternalTypedArrayE function is like CreateExternalTypedArray, but returns an *Error if the call fails.
+func CreateExternalTypedArrayE(env Env, slice interface{}) (Value, error) {
+	r0, status := CreateExternalTypedArray(env, slice)
+	return r0, CheckStatus(env, status)
+}
@@ -0,0 +1,199 @@
+package napi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Exporting Go functions
+// Export turns an ordinary Go function into a JavaScript function, converting
+// the arguments and the results with the rules described for ToJS and FromJS:
+//  func add(a, b int) int
+//  func find(db *DB, id string) (*Record, error)
+//  func sum(env napi.Env, values ...float64) float64
+// The function can optionally receive the environment as first parameter and
+// can be variadic. It can return no result, a single value, an error, or a
+// value followed by an error. A non-nil error is thrown as described for
+// ThrowGoError, while a wrong number of arguments or an argument that cannot be
+// converted is thrown as a TypeError.
+
+// ArgumentsErrorCode is the code set on the TypeError thrown when an exported
+// function is called with a wrong number of arguments.
+const ArgumentsErrorCode = "ERR_INVALID_ARGS_COUNT"
+
+// ArgumentTypeErrorCode is the code set on the TypeError thrown when an
+// argument of an exported function cannot be converted to the type of the
+// parameter.
+const ArgumentTypeErrorCode = "ERR_INVALID_ARG_TYPE"
+
+var (
+	envType   = reflect.TypeOf(Env(nil))
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Export function defines a property of exports holding a JavaScript function
+// that calls fn. It panics if fn is not a function or if its results are not
+// supported.
+// [in] env: The environment that the API is invoked under.
+// [in] exports: The object on which to define the function.
+// [in] name: The name of the function and of the property.
+// [in] fn: The Go function called when the JavaScript function is invoked.
+func Export(env Env, exports Value, name string, fn interface{}) Status {
+	res, status := CreateFunction(env, name, reflectCallback(name, fn))
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	return SetNamedProperty(env, exports, name, res)
+}
+
+// goFunc describes a Go function called from JavaScript.
+type goFunc struct {
+	name     string
+	fn       reflect.Value
+	withEnv  bool
+	params   []reflect.Type
+	variadic bool
+	hasValue bool
+	hasError bool
+}
+
+// reflectCallback returns the native callback that calls fn.
+func reflectCallback(name string, fn interface{}) CCallback {
+	f := newGoFunc(name, fn)
+	return func(env Env, info CallbackInfo) Value {
+		ctx := newCallContext(env, info)
+		if ctx.Status() != Status(Statuses.OK) {
+			return nil
+		}
+		return f.call(env, reflect.Value{}, ctx.Args())
+	}
+}
+
+// newGoFunc inspects the signature of fn, panicking if it is not supported.
+func newGoFunc(name string, fn interface{}) *goFunc {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		panic(fmt.Sprintf("napi: %s is %T, not a function", name, fn))
+	}
+	return newGoFuncValue(name, v, false)
+}
+
+// newGoFuncValue inspects the signature of the function value. If method is
+// true, the first parameter is the receiver passed to call.
+func newGoFuncValue(name string, v reflect.Value, method bool) *goFunc {
+	t := v.Type()
+	f := &goFunc{
+		name:     name,
+		fn:       v,
+		variadic: t.IsVariadic(),
+	}
+	for i := 0; i < t.NumIn(); i++ {
+		f.params = append(f.params, t.In(i))
+	}
+	if method {
+		f.params = f.params[1:]
+	}
+	if len(f.params) > 0 && f.params[0] == envType {
+		f.withEnv = true
+		f.params = f.params[1:]
+	}
+	var ok bool
+	if f.hasValue, f.hasError, ok = funcResults(t); !ok {
+		panic(fmt.Sprintf("napi: %s must return at most a value and an error, not %s", name, t))
+	}
+	return f
+}
+
+// funcResults reports whether the function type returns a value and an
+// error, and whether its results are supported.
+func funcResults(t reflect.Type) (hasValue bool, hasError bool, ok bool) {
+	switch {
+	case t.NumOut() == 0:
+	case t.NumOut() == 1 && t.Out(0) == errorType:
+		hasError = true
+	case t.NumOut() == 1:
+		hasValue = true
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+		hasValue, hasError = true, true
+	default:
+		return false, false, false
+	}
+	return hasValue, hasError, true
+}
+
+// arity describes the number of arguments accepted by the function.
+func (f *goFunc) arity() string {
+	n := len(f.params)
+	switch {
+	case f.variadic:
+		return fmt.Sprintf("at least %d argument%s", n-1, plural(n-1))
+	default:
+		return fmt.Sprintf("%d argument%s", n, plural(n))
+	}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// call converts the arguments, calls the function and converts its results.
+// The receiver, if valid, is passed before the arguments. A JavaScript
+// exception is thrown on failure.
+func (f *goFunc) call(env Env, recv reflect.Value, args []Value) Value {
+	out, ok := f.invoke(env, recv, args)
+	if !ok || !f.hasValue {
+		return nil
+	}
+	res, err := toJS(env, out[0], "result")
+	if err != nil {
+		ThrowError(env, f.name+": "+err.Error(), "")
+		return nil
+	}
+	return res
+}
+
+// invoke converts the arguments and calls the function, returning its
+// results. It returns false if a JavaScript exception has been thrown.
+func (f *goFunc) invoke(env Env, recv reflect.Value, args []Value) ([]reflect.Value, bool) {
+	fixed := len(f.params)
+	if f.variadic {
+		fixed--
+	}
+	if len(args) < fixed || (!f.variadic && len(args) > fixed) {
+		msg := fmt.Sprintf("%s: expected %s, got %d", f.name, f.arity(), len(args))
+		ThrowTypeError(env, msg, ArgumentsErrorCode)
+		return nil, false
+	}
+	in := make([]reflect.Value, 0, len(args)+2)
+	if recv.IsValid() {
+		in = append(in, recv)
+	}
+	if f.withEnv {
+		in = append(in, reflect.ValueOf(env))
+	}
+	for i, arg := range args {
+		var t reflect.Type
+		if i < fixed {
+			t = f.params[i]
+		} else {
+			t = f.params[fixed].Elem()
+		}
+		v := reflect.New(t).Elem()
+		if err := fromJS(env, arg, v, indexPath("args", i)); err != nil {
+			ThrowTypeError(env, f.name+": "+err.Error(), ArgumentTypeErrorCode)
+			return nil, false
+		}
+		in = append(in, v)
+	}
+	out := f.fn.Call(in)
+	if f.hasError {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			ThrowGoError(env, err)
+			return nil, false
+		}
+	}
+	return out, true
+}
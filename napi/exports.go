@@ -0,0 +1,138 @@
+package napi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Exports namespaces
+// Large add-ons are usually split in many Go packages, each one owning a part
+// of the JavaScript surface. Every package registers its own namespace from its
+// init function, in the same way database/sql drivers are registered:
+//  func init() {
+//  	napi.RegisterExports("crypto", func(env napi.Env, exports napi.Value) napi.Value {
+//  		napi.DefineProperties(env, exports, properties)
+//  		return nil
+//  	})
+//  }
+// When the add-on is loaded every namespace becomes a property of the add-on
+// exports, holding the object populated by the registered function. Name
+// collisions, between namespaces or with the properties declared on Module, are
+// reported as errors thrown by require().
+
+// ExportsCollisionErrorCode is the code set on the JavaScript error thrown when
+// the add-on is loaded with colliding export names.
+const ExportsCollisionErrorCode = "ERR_NAPI_EXPORTS_COLLISION"
+
+// This is a struct used as container for the registered namespaces.
+type exportsRegistry struct {
+	mu         sync.Mutex
+	namespaces map[string]ModuleInit
+	collisions []string
+}
+
+var exportsNamespaces = &exportsRegistry{
+	namespaces: make(map[string]ModuleInit),
+}
+
+// RegisterExports function registers a namespace of the add-on exports. The
+// function is called when the add-on is loaded with a new object, that will be
+// set as the namespace property of the exports. If the function returns a non
+// nil value, that value is used for the namespace instead.
+// [in] namespace: The name of the property of the exports holding the
+// namespace.
+// [in] fn: The function that populates the namespace.
+// Registering the same namespace twice is reported as an error when the add-on
+// is loaded.
+func RegisterExports(namespace string, fn ModuleInit) {
+	if fn == nil {
+		panic("napi: RegisterExports function is nil")
+	}
+	exportsNamespaces.register(namespace, fn)
+}
+
+// ExportsNamespaces function returns a sorted list of the registered
+// namespaces.
+func ExportsNamespaces() []string {
+	return exportsNamespaces.names()
+}
+
+func (r *exportsRegistry) register(namespace string, fn ModuleInit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.namespaces[namespace]; dup {
+		r.collisions = append(r.collisions, fmt.Sprintf("namespace %q is registered more than once", namespace))
+		return
+	}
+	r.namespaces[namespace] = fn
+}
+
+func (r *exportsRegistry) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.namespaces))
+	for name := range r.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check returns the collisions between the registered namespaces and the
+// given names already used by the exports.
+func (r *exportsRegistry) check(used []string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	collisions := append([]string(nil), r.collisions...)
+	for _, name := range used {
+		if _, ok := r.namespaces[name]; ok {
+			collisions = append(collisions, fmt.Sprintf("namespace %q collides with an export of the module", name))
+		}
+	}
+	return collisions
+}
+
+// merge creates every registered namespace and sets it on the exports. It
+// returns false if a JavaScript exception has been thrown.
+func (r *exportsRegistry) merge(env Env, exports Value, used []string) bool {
+	if collisions := r.check(used); len(collisions) > 0 {
+		ThrowError(env, "napi: "+strings.Join(collisions, "; "), ExportsCollisionErrorCode)
+		return false
+	}
+	for _, name := range r.names() {
+		r.mu.Lock()
+		fn := r.namespaces[name]
+		r.mu.Unlock()
+		// Only the own properties collide, not the ones inherited from
+		// Object.prototype like toString.
+		key, status := CreateStringUtf8(env, name)
+		if status != Status(Statuses.OK) {
+			return false
+		}
+		exists, status := HasOwnProperty(env, exports, key)
+		if status != Status(Statuses.OK) {
+			return false
+		}
+		if exists {
+			msg := fmt.Sprintf("napi: namespace %q collides with an existing property of the exports", name)
+			ThrowError(env, msg, ExportsCollisionErrorCode)
+			return false
+		}
+		namespace, status := CreateObject(env)
+		if status != Status(Statuses.OK) {
+			return false
+		}
+		if res := fn(env, namespace); res != nil {
+			namespace = res
+		}
+		if pending, _ := IsExceptionPending(env); pending {
+			return false
+		}
+		if status := SetNamedProperty(env, exports, name, namespace); status != Status(Statuses.OK) {
+			return false
+		}
+	}
+	return true
+}
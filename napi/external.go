@@ -0,0 +1,319 @@
+package napi
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// External memory shared with JavaScript
+// Memory allocated by Go cannot be handed to JavaScript without copying it:
+// the rules for passing pointers between Go and C forbid C code, and V8, to
+// keep a Go pointer after the call returns. Memory meant to be shared is
+// allocated by C instead, with AllocExternal:
+//  data := napi.AllocExternal(size)
+//  render(data)
+//  buf, status := napi.CreateGoBuffer(env, data, nil)
+// The memory is freed with C.free once the Buffer is garbage-collected, or
+// handed back to the release callback, on the main thread, that becomes
+// responsible for it and can reuse it or free it with FreeExternal:
+//  buf, status := napi.CreateGoBuffer(env, data, func(data []byte) {
+//  	pool.Put(data)
+//  })
+// The memory must not be modified by other goroutines while JavaScript may
+// access it. Any other slice, like one allocated by Go, is copied, and its
+// release callback is called right away.
+// The size of the shared memory is reported to V8 with AdjustExternalMemory
+// while it is alive, so that garbage collections are scheduled as if the
+// memory was allocated by JavaScript.
+// TransferArrayBuffer hands the memory of an ArrayBuffer back to Go, detaching
+// it so that JavaScript can no longer access it, like postMessage does when it
+// transfers an ArrayBuffer to a worker:
+//  data, status := napi.TransferArrayBuffer(env, arraybuffer)
+// The memory shared with AllocExternal is returned without copying it.
+
+// This is a struct used as container for the memory allocated by
+// AllocExternal, indexed by address.
+type allocationRegistry struct {
+	mu    sync.Mutex
+	sizes map[uintptr]int
+}
+
+var externalAllocations = &allocationRegistry{
+	sizes: make(map[uintptr]int),
+}
+
+// contains reports whether the length bytes at data are the start of an
+// allocation made by AllocExternal.
+func (r *allocationRegistry) contains(data uintptr, length int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	size, ok := r.sizes[data]
+	return ok && length <= size
+}
+
+// AllocExternal function allocates n bytes of memory with C.malloc, that can
+// be shared with JavaScript without copying it, see CreateGoBuffer. The memory
+// is zeroed. It panics if the memory cannot be allocated.
+// [in] n: The number of bytes to allocate.
+func AllocExternal(n int) []byte {
+	var res []byte
+	allocExternal(unsafe.Pointer(&res), n, 1)
+	return res
+}
+
+// allocExternal sets the slice pointed to by slice to n elements of size
+// bytes allocated with C.malloc.
+func allocExternal(slice unsafe.Pointer, n int, size int) {
+	if n < 0 {
+		panic("napi: negative external allocation")
+	}
+	if n == 0 {
+		setSlice(slice, nil, 0)
+		return
+	}
+	data := C.calloc(C.size_t(n), C.size_t(size))
+	if data == nil {
+		panic("napi: cannot allocate external memory")
+	}
+	externalAllocations.mu.Lock()
+	externalAllocations.sizes[uintptr(data)] = n * size
+	externalAllocations.mu.Unlock()
+	setSlice(slice, data, n)
+}
+
+// FreeExternal function frees the memory allocated by AllocExternal or
+// AllocExternalSlice. It panics if slice is not such an allocation or if the
+// memory is shared with JavaScript.
+// [in] slice: The slice returned by AllocExternal, or set by
+// AllocExternalSlice, or one starting at the same address. Empty slices are
+// ignored.
+func FreeExternal(slice interface{}) {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("napi: FreeExternal of %T, not a slice", slice))
+	}
+	if rv.Cap() == 0 {
+		return
+	}
+	freeExternal(unsafe.Pointer(rv.Pointer()))
+}
+
+// freeExternal frees the allocation starting at data.
+func freeExternal(data unsafe.Pointer) {
+	if sharedMemory.get(uintptr(data)) != nil {
+		panic("napi: FreeExternal of memory shared with JavaScript")
+	}
+	externalAllocations.mu.Lock()
+	_, ok := externalAllocations.sizes[uintptr(data)]
+	delete(externalAllocations.sizes, uintptr(data))
+	externalAllocations.mu.Unlock()
+	if !ok {
+		panic("napi: FreeExternal of memory not allocated by AllocExternal")
+	}
+	C.free(data)
+}
+
+// externalMemory is the memory allocated by AllocExternal that is shared with
+// JavaScript.
+type externalMemory struct {
+	data    unsafe.Pointer
+	length  int64
+	release func()
+}
+
+// finalize releases the memory once its JavaScript value is collected.
+func (m *externalMemory) finalize(env Env, data unsafe.Pointer, hint unsafe.Pointer) {
+	if !sharedMemory.remove(m) {
+		// The memory has been transferred back to Go.
+		return
+	}
+	AdjustExternalMemory(env, -m.length)
+	if m.release != nil {
+		m.release()
+		return
+	}
+	freeExternal(m.data)
+}
+
+// This is a struct used as container for the memory shared with JavaScript,
+// indexed by address.
+type memoryRegistry struct {
+	mu     sync.Mutex
+	owners map[uintptr]*externalMemory
+}
+
+var sharedMemory = &memoryRegistry{
+	owners: make(map[uintptr]*externalMemory),
+}
+
+// add registers the memory, unless the same memory is already shared.
+func (r *memoryRegistry) add(m *externalMemory) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.owners[uintptr(m.data)]; ok {
+		return false
+	}
+	r.owners[uintptr(m.data)] = m
+	return true
+}
+
+// get returns the owner of the memory at data, if it is shared.
+func (r *memoryRegistry) get(data uintptr) *externalMemory {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.owners[data]
+}
+
+// remove forgets the memory, reporting whether it was shared.
+func (r *memoryRegistry) remove(m *externalMemory) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.owners[uintptr(m.data)] != m {
+		return false
+	}
+	delete(r.owners, uintptr(m.data))
+	return true
+}
+
+// externalCreator creates a JavaScript value over external memory, like
+// CreateExternalBuffer and CreateExternalArrayBuffer.
+type externalCreator func(env Env, length uint, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, Status)
+
+// shareExternal creates a JavaScript value over length bytes of memory at
+// data, if they have been allocated by AllocExternal and are not shared yet.
+// It reports false, without creating the value, otherwise.
+func shareExternal(env Env, create externalCreator, data unsafe.Pointer, length int, release func()) (Value, bool, Status) {
+	if !externalAllocations.contains(uintptr(data), length) {
+		return nil, false, Status(Statuses.OK)
+	}
+	memory := &externalMemory{data: data, length: int64(length), release: release}
+	if !sharedMemory.add(memory) {
+		return nil, false, Status(Statuses.OK)
+	}
+	res, status := create(env, uint(length), data, &FinalizeCaller{Cb: memory.finalize}, nil)
+	if status != Status(Statuses.OK) {
+		sharedMemory.remove(memory)
+		return nil, true, status
+	}
+	AdjustExternalMemory(env, memory.length)
+	return res, true, status
+}
+
+// bytesRelease returns the function that calls release with data, if any.
+func bytesRelease(data []byte, release func([]byte)) func() {
+	if release == nil {
+		return nil
+	}
+	return func() { release(data) }
+}
+
+// CreateGoBuffer function creates a Buffer over data. The memory allocated by
+// AllocExternal is shared without copying it, while any other slice is
+// copied.
+// [in] env: The environment that the API is invoked under.
+// [in] data: The memory of the Buffer.
+// [in] release: Optional callback called with data once the memory is no
+// longer used by JavaScript: once the Buffer is garbage-collected if it is
+// shared, right away if it is copied. If it is nil, the shared memory is
+// freed with FreeExternal.
+func CreateGoBuffer(env Env, data []byte, release func([]byte)) (Value, Status) {
+	if len(data) > 0 {
+		res, shared, status := shareExternal(env, CreateExternalBuffer, unsafe.Pointer(&data[0]), len(data), bytesRelease(data, release))
+		if shared {
+			return res, status
+		}
+	}
+	res, status := createBufferCopy(env, data)
+	if status == Status(Statuses.OK) && release != nil {
+		release(data)
+	}
+	return res, status
+}
+
+// CreateGoArrayBuffer function creates an ArrayBuffer over data. The memory
+// allocated by AllocExternal is shared without copying it, while any other
+// slice is copied.
+// [in] env: The environment that the API is invoked under.
+// [in] data: The memory of the ArrayBuffer.
+// [in] release: Optional callback called with data once the memory is no
+// longer used by JavaScript: once the ArrayBuffer is garbage-collected if it
+// is shared, right away if it is copied. If it is nil, the shared memory is
+// freed with FreeExternal.
+func CreateGoArrayBuffer(env Env, data []byte, release func([]byte)) (Value, Status) {
+	if len(data) > 0 {
+		res, shared, status := shareExternal(env, CreateExternalArrayBuffer, unsafe.Pointer(&data[0]), len(data), bytesRelease(data, release))
+		if shared {
+			return res, status
+		}
+	}
+	res, buf, status := CreateArrayBuffer(env, uint(len(data)))
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if len(data) > 0 {
+		var view []byte
+		setSlice(unsafe.Pointer(&view), buf, len(data))
+		copy(view, data)
+	}
+	if release != nil {
+		release(data)
+	}
+	return res, status
+}
+
+// createBufferCopy creates a Buffer holding a copy of data.
+func createBufferCopy(env Env, data []byte) (Value, Status) {
+	res, buf, status := CreateBuffer(env, uint(len(data)))
+	if status == Status(Statuses.OK) && len(data) > 0 {
+		var view []byte
+		setSlice(unsafe.Pointer(&view), buf, len(data))
+		copy(view, data)
+	}
+	return res, status
+}
+
+// TransferArrayBuffer function detaches an ArrayBuffer and returns its memory.
+// The memory of the ArrayBuffers created over memory allocated by
+// AllocExternal, with CreateGoArrayBuffer or CreateExternalTypedArray, is
+// returned without copying it: its release callback is not called, so the
+// caller becomes responsible for it and frees it with FreeExternal. The memory
+// of any other ArrayBuffer is copied to Go memory before detaching it.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The ArrayBuffer to transfer.
+// Returns napi_detachable_arraybuffer_expected if the ArrayBuffer cannot be
+// detached. An ArrayBuffer that has already been detached is returned as an
+// empty slice.
+// N-API version: 7
+func TransferArrayBuffer(env Env, value Value) ([]byte, Status) {
+	data, length, status := GetArrayBufferInfo(env, value)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	var res []byte
+	memory := sharedMemory.get(uintptr(data))
+	if memory != nil && memory.length != int64(length) {
+		memory = nil
+	}
+	if length > 0 && data != nil {
+		setSlice(unsafe.Pointer(&res), data, int(length))
+		if memory == nil {
+			res = append([]byte(nil), res...)
+		}
+	}
+	if status := DetachArrayBuffer(env, value); status != Status(Statuses.OK) {
+		return nil, status
+	}
+	// The memory is no longer used by JavaScript, while the finalizer is
+	// called later, once the detached ArrayBuffer is collected, and then
+	// leaves the memory alone.
+	if memory != nil && sharedMemory.remove(memory) {
+		AdjustExternalMemory(env, -memory.length)
+	}
+	return res, status
+}
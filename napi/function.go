@@ -0,0 +1,67 @@
+package napi
+
+// Go closures as JavaScript functions
+// NewFunction creates a JavaScript function that calls a Go closure, so that
+// every function created can capture its own state:
+//  func counter(env napi.Env, start int64) (napi.Function, napi.Status) {
+//  	n := start
+//  	return napi.NewFunction(env, "next", 1, func(ctx *napi.CallContext) napi.Value {
+//  		step, err := ctx.Arg(0).Int64(1)
+//  		if err != nil {
+//  			return ctx.ThrowTypeError(err.Error())
+//  		}
+//  		n += step
+//  		res, _ := napi.CreateInt64(ctx.Env(), n)
+//  		return res
+//  	})
+//  }
+// The closure, and the variables it captures, live as long as the JavaScript
+// function: they are released once the function is garbage-collected.
+
+// FunctionCallback represents a Go closure called from JavaScript. The value
+// returned is the result of the call, and nil is undefined.
+type FunctionCallback func(ctx *CallContext) Value
+
+// CCallback returns the CCallback that calls cb with the context of the call.
+func (cb FunctionCallback) CCallback() CCallback {
+	return func(env Env, info CallbackInfo) Value {
+		return cb(newCallContext(env, info))
+	}
+}
+
+// NewFunction function creates a JavaScript function that calls the Go
+// closure cb. The closure is released once the function is garbage-collected.
+// [in] env: The environment that the API is invoked under.
+// [in] name: The name property of the function.
+// [in] length: The length property of the function, that is the number of
+// arguments it expects. A negative length returns napi_invalid_arg.
+// [in] cb: The Go closure called when the function is invoked.
+func NewFunction(env Env, name string, length int, cb FunctionCallback) (Function, Status) {
+	if length < 0 {
+		return Function{}, Status(Statuses.InvalidArg)
+	}
+	value, status := CreateFunction(env, name, cb.CCallback())
+	if status != Status(Statuses.OK) {
+		return Function{}, status
+	}
+	if length != 0 {
+		if status = setFunctionLength(env, value, length); status != Status(Statuses.OK) {
+			return Function{}, status
+		}
+	}
+	return Function{Object{env: env, value: value}}, status
+}
+
+// setFunctionLength redefines the length property of the function, that
+// N-API always sets to 0, with the attributes of the built-in property.
+func setFunctionLength(env Env, fn Value, length int) Status {
+	value, status := CreateInt64(env, int64(length))
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	return DefineProperties(env, fn, []Property{{
+		Name:       "length",
+		Value:      value,
+		Attributes: PropertyAttributes.Configurable,
+	}})
+}
@@ -0,0 +1,142 @@
+//go:build ignore
+// +build ignore
+
+// This program generates errors_api.go, the variants of the N-API functions
+// that return an error instead of a Status. It is run by go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// skipped are the files that cannot declare the functions returning a Status.
+var skipped = map[string]bool{
+	"errors_api.go": true,
+	"gen_errors.go": true,
+}
+
+func main() {
+	fset := token.NewFileSet()
+	names, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var funcs []*ast.FuncDecl
+	for _, name := range names {
+		if skipped[name] || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && returnsStatus(fn) {
+				funcs = append(funcs, fn)
+			}
+		}
+	}
+	sort.Slice(funcs, func(i, j int) bool { return funcs[i].Pos() < funcs[j].Pos() })
+
+	var buf bytes.Buffer
+	var body bytes.Buffer
+	for _, fn := range funcs {
+		writeFunc(&body, fset, fn)
+	}
+	buf.WriteString("// Code generated by gen_errors.go; DO NOT EDIT.\n\npackage napi\n\nimport (\n")
+	for _, pkg := range []string{"math/big", "time", "unsafe"} {
+		if bytes.Contains(body.Bytes(), []byte(pkg[strings.LastIndex(pkg, "/")+1:]+".")) {
+			fmt.Fprintf(&buf, "\t%q\n", pkg)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		os.Stdout.Write(buf.Bytes())
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("errors_api.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// returnsStatus reports whether fn is an exported function whose last result
+// is a Status.
+func returnsStatus(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || !fn.Name.IsExported() || fn.Type.Results == nil {
+		return false
+	}
+	results := fn.Type.Results.List
+	last, ok := results[len(results)-1].Type.(*ast.Ident)
+	return ok && last.Name == "Status"
+}
+
+// writeFunc writes the variant of fn that returns an error.
+func writeFunc(buf *bytes.Buffer, fset *token.FileSet, fn *ast.FuncDecl) {
+	var params, args []string
+	env := ""
+	for i, field := range fn.Type.Params.List {
+		typ := typeString(fset, field.Type)
+		for _, name := range field.Names {
+			// A parameter named error would shadow the error type.
+			pname := name.Name
+			if pname == "error" {
+				pname = "value"
+			}
+			params = append(params, pname+" "+typ)
+			arg := pname
+			if strings.HasPrefix(typ, "...") {
+				arg += "..."
+			}
+			args = append(args, arg)
+			if i == 0 && typ == "Env" {
+				env = pname
+			}
+		}
+	}
+	var results, values []string
+	list := fn.Type.Results.List
+	for i, field := range list[:len(list)-1] {
+		results = append(results, typeString(fset, field.Type))
+		values = append(values, fmt.Sprintf("r%d", i))
+	}
+	check := "errorOf(status)"
+	if env != "" {
+		check = "CheckStatus(" + env + ", status)"
+	}
+	name := fn.Name.Name
+	fmt.Fprintf(buf, "// %sE function is like %s, but returns an *Error if the call fails.\n", name, name)
+	out := "error"
+	if len(results) > 0 {
+		out = "(" + strings.Join(append(results, "error"), ", ") + ")"
+	}
+	fmt.Fprintf(buf, "func %sE(%s) %s {\n", name, strings.Join(params, ", "), out)
+	call := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if len(values) == 0 {
+		check = strings.Replace(check, "status", call, 1)
+		fmt.Fprintf(buf, "\treturn %s\n}\n\n", check)
+		return
+	}
+	fmt.Fprintf(buf, "\t%s, status := %s\n", strings.Join(values, ", "), call)
+	fmt.Fprintf(buf, "\treturn %s, %s\n}\n\n", strings.Join(values, ", "), check)
+}
+
+// typeString returns the source of the type expression.
+func typeString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
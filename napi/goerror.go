@@ -0,0 +1,208 @@
+package napi
+
+import (
+	"errors"
+)
+
+// Throwing Go errors
+// ThrowGoError throws a Go error as a JavaScript exception, choosing the
+// JavaScript error from the error chain:
+//  - a *JSError is thrown again as the original exception;
+//  - an error with an Unwrap() []error method, like the ones returned by
+//    errors.Join, becomes an AggregateError of the wrapped errors;
+//  - an error of a type registered with RegisterErrorClass becomes an
+//    instance of its class;
+//  - an error wrapped with TypeError or RangeError, or implementing
+//    JSErrorClass, becomes a TypeError or a RangeError;
+//  - any other error becomes an Error.
+// The class is chosen by the first error of the chain that is registered or
+// implements JSErrorClass. The code property is set from the first error of
+// the chain implementing JSErrorCoder, and the cause property is set to the
+// first *JSError wrapped in the chain. The chain is followed with
+// errors.Unwrap, so the errors wrapped by a multiple error only affect their
+// own element of the AggregateError. The message is always the one of the Go error.
+// Callbacks of type ErrCallback return errors that are thrown this way:
+//  napi.Module.Function("open", napi.ErrCallback(func(env napi.Env, info napi.CallbackInfo) (napi.Value, error) {
+//  	if err := check(); err != nil {
+//  		return nil, napi.TypeError(err)
+//  	}
+//  	...
+//  }).CCallback())
+
+// JSErrorCoder is implemented by errors that set the code property of the
+// JavaScript error they are thrown as.
+type JSErrorCoder interface {
+	JSErrorCode() string
+}
+
+// JSErrorClass is implemented by errors that choose the class of the
+// JavaScript error they are thrown as: "Error", "TypeError" or "RangeError".
+type JSErrorClass interface {
+	JSErrorClass() string
+}
+
+// ErrCallback represents a native function that reports failures by returning
+// an error, thrown as described for ThrowGoError.
+type ErrCallback func(env Env, info CallbackInfo) (Value, error)
+
+// CCallback returns the CCallback that calls cb and throws its error.
+func (cb ErrCallback) CCallback() CCallback {
+	return func(env Env, info CallbackInfo) Value {
+		res, err := cb(env, info)
+		if err != nil {
+			ThrowGoError(env, err)
+			return nil
+		}
+		return res
+	}
+}
+
+// classError is an error that chooses the class of the JavaScript error.
+type classError struct {
+	err   error
+	class string
+}
+
+func (e *classError) Error() string        { return e.err.Error() }
+func (e *classError) Unwrap() error        { return e.err }
+func (e *classError) JSErrorClass() string { return e.class }
+
+// codeError is an error that sets the code of the JavaScript error.
+type codeError struct {
+	err  error
+	code string
+}
+
+func (e *codeError) Error() string       { return e.err.Error() }
+func (e *codeError) Unwrap() error       { return e.err }
+func (e *codeError) JSErrorCode() string { return e.code }
+
+// TypeError function wraps err so that it is thrown as a JavaScript
+// TypeError.
+// [in] err: The wrapped error.
+func TypeError(err error) error {
+	return &classError{err: err, class: "TypeError"}
+}
+
+// RangeError function wraps err so that it is thrown as a JavaScript
+// RangeError.
+// [in] err: The wrapped error.
+func RangeError(err error) error {
+	return &classError{err: err, class: "RangeError"}
+}
+
+// WithCode function wraps err so that the JavaScript error it is thrown as
+// has the given code.
+// [in] err: The wrapped error.
+// [in] code: The code of the JavaScript error.
+func WithCode(err error, code string) error {
+	return &codeError{err: err, code: code}
+}
+
+// JSErrorClass reports conversion errors as TypeError.
+func (e *ConversionError) JSErrorClass() string {
+	return "TypeError"
+}
+
+// ThrowGoError function throws err as a JavaScript error.
+// [in] env: The environment that the API is invoked under.
+// [in] err: The Go error to throw.
+func ThrowGoError(env Env, err error) Status {
+	res, status := CreateGoError(env, err)
+	if status != Status(Statuses.OK) {
+		return ThrowError(env, err.Error(), "")
+	}
+	return Throw(env, res)
+}
+
+// CreateGoError function creates the JavaScript error that ThrowGoError
+// throws for err, for example to reject a promise.
+// [in] env: The environment that the API is invoked under.
+// [in] err: The Go error to convert.
+func CreateGoError(env Env, err error) (Value, Status) {
+	if jsErr, ok := err.(*JSError); ok && jsErr.Value != nil {
+		return jsErr.Value, Status(Statuses.OK)
+	}
+	msg, status := CreateStringUtf8(env, err.Error())
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	// Only the chain of err is inspected: the errors wrapped by a multiple
+	// error are converted on their own.
+	var code Value
+	var class string
+	var registered *ErrorClass
+	var matched error
+	var multi []error
+	var cause *JSError
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if coder, ok := e.(JSErrorCoder); ok && code == nil {
+			if code, status = CreateStringUtf8(env, coder.JSErrorCode()); status != Status(Statuses.OK) {
+				return nil, status
+			}
+		}
+		if class == "" && registered == nil {
+			if registered = errorClassOf(e); registered != nil {
+				matched = e
+			} else if c, ok := e.(JSErrorClass); ok {
+				class = c.JSErrorClass()
+			}
+		}
+		if jsErr, ok := e.(*JSError); ok && cause == nil && jsErr.Value != nil {
+			cause = jsErr
+		}
+		if m, ok := e.(interface{ Unwrap() []error }); ok {
+			multi = m.Unwrap()
+			break
+		}
+	}
+	var res Value
+	switch {
+	case multi != nil:
+		res, status = createAggregateError(env, msg, multi)
+		if status == Status(Statuses.OK) && code != nil {
+			status = SetNamedProperty(env, res, "code", code)
+		}
+	case registered != nil:
+		res, status = registered.newInstance(env, msg, code, matched)
+	case class == "TypeError":
+		res, status = CreateTypeError(env, code, msg)
+	case class == "RangeError":
+		res, status = CreateRangeError(env, code, msg)
+	default:
+		res, status = CreateError(env, msg, code)
+	}
+	if status == Status(Statuses.OK) && cause != nil {
+		status = SetNamedProperty(env, res, "cause", cause.Value)
+	}
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	return res, status
+}
+
+// createAggregateError creates an AggregateError of the errors.
+func createAggregateError(env Env, msg Value, errs []error) (Value, Status) {
+	global, status := GetGlobal(env)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	ctor, status := GetNamedProperty(env, global, "AggregateError")
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	values, status := CreateArrayWithLength(env, uint(len(errs)))
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	for i, err := range errs {
+		value, status := CreateGoError(env, err)
+		if status != Status(Statuses.OK) {
+			return nil, status
+		}
+		if status := SetElement(env, values, uint(i), value); status != Status(Statuses.OK) {
+			return nil, status
+		}
+	}
+	return NewInstance(env, ctor, []Value{values, msg})
+}
@@ -1,125 +1,293 @@
 #include "gonapi.h"
-#include <stdio.h>
-#include <utility>
-#include <vector>
-#include <cassert>
 
 #include "_cgo_export.h"
 
+// Trampolines
+// N-API only accepts plain C function pointers, so a single trampoline exists
+// for every kind of callback. The Go side registers each callback in its
+// registry and passes the resulting handle as the data (or hint, or context)
+// pointer of the N-API call. The trampolines read the handle back and forward
+// the call to the Go dispatcher, which routes it to the registered callback.
 
-napi_value MethodP(napi_env env, napi_callback_info info) {
-    napi_status status;
-    napi_value world;
-    status = napi_create_string_utf8(env, "world", 5, &world);
-    assert(status == napi_ok);
-        return world;
+static inline void* ToPointer(uintptr_t handle) {
+  return reinterpret_cast<void*>(handle);
 }
 
-struct Context{
-    void* data;
-    Context(void* data) : data(data) {}
-};
+static inline uintptr_t ToHandle(void* data) {
+  return reinterpret_cast<uintptr_t>(data);
+}
 
-static std::vector<Context*> m;
+static napi_value CallbackTrampoline(napi_env env, napi_callback_info info) {
+  void* data = nullptr;
+  napi_status status = napi_get_cb_info(env, info, nullptr, nullptr, nullptr, &data);
+  if (status != napi_ok) {
+    return nullptr;
+  }
+  return CallCallback(ToHandle(data), env, info);
+}
 
-static std::vector<void*> registry{};
-struct AsyncExecuteCallbackWrap {
-  AsyncExecuteCallbackWrap(void* data) : data{data} {}
-  napi_async_execute_callback operator()() {
-    static auto dataCopy = data;
-    return [](napi_env env, void* data) -> void {
-        return CallAsyncExecuteCallback(dataCopy, env, data);
-    };
+static napi_value GetterTrampoline(napi_env env, napi_callback_info info) {
+  void* data = nullptr;
+  napi_status status = napi_get_cb_info(env, info, nullptr, nullptr, nullptr, &data);
+  if (status != napi_ok) {
+    return nullptr;
   }
-  void* data;
-};
-
-struct AsyncCompleteCallbackWrap {
-  AsyncCompleteCallbackWrap(void* data) : data{data} {}
-  napi_async_complete_callback operator()() {
-    static auto dataCopy = data;
-    return [](napi_env env, napi_status status, void* data) -> void {
-        return CallAsyncCompleteCallback(dataCopy, env, status, data);
-    };
+  return CallGetterCallback(ToHandle(data), env, info);
+}
+
+static napi_value SetterTrampoline(napi_env env, napi_callback_info info) {
+  void* data = nullptr;
+  napi_status status = napi_get_cb_info(env, info, nullptr, nullptr, nullptr, &data);
+  if (status != napi_ok) {
+    return nullptr;
   }
-  void* data;
-};
-
-struct FinalizeCallbackWrap {
-  FinalizeCallbackWrap(void* data) : data{data} {}
-  napi_finalize operator()() {
-    static auto dataCopy = data;
-    return [](napi_env env, void* data, void* hint) -> void {
-        return CallFinalizeCallback(dataCopy, env, data, hint);
-    };
+  return CallSetterCallback(ToHandle(data), env, info);
+}
+
+static void AsyncExecuteTrampoline(napi_env env, void* data) {
+  CallAsyncExecuteCallback(ToHandle(data), env);
+}
+
+static void AsyncCompleteTrampoline(napi_env env, napi_status status, void* data) {
+  CallAsyncCompleteCallback(ToHandle(data), env, status);
+}
+
+static void FinalizeTrampoline(napi_env env, void* data, void* hint) {
+  CallFinalizeCallback(ToHandle(hint), env, data);
+}
+
+static void ThreadsafeFunctionTrampoline(napi_env env,
+                                         napi_value callback,
+                                         void* context,
+                                         void* data) {
+  CallThreadsafeFunctionCallback(ToHandle(context), env, callback, data);
+}
+
+static void ReleaseTrampoline(napi_env env, void* data, void* hint) {
+  ReleaseHandle(ToHandle(hint));
+}
+
+static void InstanceDataFinalizeTrampoline(napi_env env, void* data, void* hint) {
+  CallInstanceDataFinalizeCallback(ToHandle(data), env);
+}
+
+static void EnvCleanupTrampoline(void* arg) {
+  CallEnvCleanupHook(ToHandle(arg));
+}
+
+static void AsyncCleanupTrampoline(napi_async_cleanup_hook_handle handle,
+                                   void* arg) {
+  CallAsyncCleanupHook(ToHandle(arg), handle);
+}
+
+static void ThreadsafeFunctionFinalizeTrampoline(napi_env env,
+                                                 void* data,
+                                                 void* context) {
+  CallThreadsafeFunctionFinalizeCallback(ToHandle(context), env, data);
+}
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+napi_callback Callback() {
+  return CallbackTrampoline;
+}
+
+napi_async_execute_callback AsyncExecuteCallback() {
+  return AsyncExecuteTrampoline;
+}
+
+napi_async_complete_callback AsyncCompleteCallback() {
+  return AsyncCompleteTrampoline;
+}
+
+napi_finalize FinalizeCallback() {
+  return FinalizeTrampoline;
+}
+
+napi_threadsafe_function_call_js ThreadsafeFunctionCallback() {
+  return ThreadsafeFunctionTrampoline;
+}
+
+napi_finalize ThreadsafeFunctionFinalizeCallback() {
+  return ThreadsafeFunctionFinalizeTrampoline;
+}
+
+napi_status AttachHandles(napi_env env,
+                          napi_value js_object,
+                          const uintptr_t* handles,
+                          size_t count,
+                          size_t* attached) {
+  for (size_t i = 0; i < count; i++) {
+    napi_status status = napi_add_finalizer(env, js_object, nullptr,
+                                            ReleaseTrampoline,
+                                            ToPointer(handles[i]), nullptr);
+    if (status != napi_ok) {
+      if (attached != nullptr) {
+        *attached = i;
+      }
+      return status;
+    }
   }
-  void* data;
-};
-
-struct ThreadsafeFunctionCallbackWrap {
-  ThreadsafeFunctionCallbackWrap(void* data) : data{data} {}
-  napi_threadsafe_function_call_js operator()() {
-    static auto dataCopy = data;
-    return [](napi_env env, napi_value callback, void* ctx, void* data) -> void {
-        return CallThreadsafeFunctionCallback(dataCopy, env, callback, ctx, data);
-    };
+  if (attached != nullptr) {
+    *attached = count;
   }
-  void* data;
-};
-
-struct CallbackWrap {
-  CallbackWrap(void* data) : data{data} {}
-  static inline
-  napi_value Wrapper(napi_env env, napi_callback_info info) {
-    printf("Registry %p\n", registry[0]);
-    return CallCallback(registry[0], env, info);
+  return napi_ok;
+}
+
+void SetPropertyCallbacks(napi_property_descriptor* desc,
+                          uintptr_t property,
+                          bool method,
+                          bool getter,
+                          bool setter) {
+  desc->method = method ? CallbackTrampoline : nullptr;
+  desc->getter = getter ? GetterTrampoline : nullptr;
+  desc->setter = setter ? SetterTrampoline : nullptr;
+  desc->data = ToPointer(property);
+}
+
+napi_status CreateFunction(napi_env env,
+                           const char* utf8name,
+                           size_t length,
+                           uintptr_t caller,
+                           napi_value* result) {
+  napi_status status = napi_create_function(env, utf8name, length,
+                                            CallbackTrampoline,
+                                            ToPointer(caller), result);
+  if (status != napi_ok) {
+    return status;
   }
-  napi_callback operator()() {
-    return [](napi_env env, napi_callback_info info) -> napi_value {
-        return CallCallback(registry[0], env, info);
-    };
+  return AttachHandles(env, *result, &caller, 1, nullptr);
+}
+
+napi_status DefineClass(napi_env env,
+                        const char* utf8name,
+                        size_t length,
+                        uintptr_t constructor,
+                        size_t property_count,
+                        const napi_property_descriptor* properties,
+                        napi_value* result) {
+  napi_status status = napi_define_class(env, utf8name, length,
+                                         CallbackTrampoline,
+                                         ToPointer(constructor),
+                                         property_count, properties, result);
+  if (status != napi_ok) {
+    return status;
   }
-  void* data;
-};
+  return AttachHandles(env, *result, &constructor, 1, nullptr);
+}
 
+napi_status WrapHandle(napi_env env, napi_value js_object, uintptr_t wrapped) {
+  return napi_wrap(env, js_object, ToPointer(wrapped), ReleaseTrampoline,
+                   ToPointer(wrapped), nullptr);
+}
 
+napi_status AddFinalizer(napi_env env,
+                         napi_value js_object,
+                         void* native_object,
+                         uintptr_t finalizer,
+                         napi_ref* result) {
+  return napi_add_finalizer(env, js_object, native_object, FinalizeTrampoline,
+                            ToPointer(finalizer), result);
+}
 
-#ifdef __cplusplus
-extern "C" {
-#endif
+napi_status CreateExternal(napi_env env,
+                           void* data,
+                           uintptr_t finalizer,
+                           napi_value* result) {
+  return napi_create_external(env, data, FinalizeTrampoline,
+                              ToPointer(finalizer), result);
+}
 
+napi_status CreateExternalArrayBuffer(napi_env env,
+                                      void* external_data,
+                                      size_t byte_length,
+                                      uintptr_t finalizer,
+                                      napi_value* result) {
+  return napi_create_external_arraybuffer(env, external_data, byte_length,
+                                          FinalizeTrampoline,
+                                          ToPointer(finalizer), result);
+}
 
-napi_callback Callback(void* caller) {
-  printf("Callback called\n");
-  registry.push_back(caller);
-  printf("Registry %p\n", registry[0]);
-  return [](napi_env env, napi_callback_info info) -> napi_value {
-        return CallCallback(registry[0], env, info);
-    };
+napi_status CreateExternalBuffer(napi_env env,
+                                 size_t length,
+                                 void* data,
+                                 uintptr_t finalizer,
+                                 napi_value* result) {
+  return napi_create_external_buffer(env, length, data, FinalizeTrampoline,
+                                     ToPointer(finalizer), result);
 }
 
-napi_async_execute_callback AsyncExecuteCallback(void* caller) {
-  AsyncExecuteCallbackWrap cb{caller};
-  return cb();
+napi_status CreateAsyncWork(napi_env env,
+                            napi_value async_resource,
+                            napi_value async_resource_name,
+                            uintptr_t work,
+                            napi_async_work* result) {
+  return napi_create_async_work(env, async_resource, async_resource_name,
+                                AsyncExecuteTrampoline, AsyncCompleteTrampoline,
+                                ToPointer(work), result);
+}
 
+napi_status CreateThreadsafeFunction(napi_env env,
+                                     napi_value func,
+                                     napi_value async_resource,
+                                     napi_value async_resource_name,
+                                     size_t max_queue_size,
+                                     size_t initial_thread_count,
+                                     void* thread_finalize_data,
+                                     uintptr_t tsfn,
+                                     bool call_js,
+                                     napi_threadsafe_function* result) {
+  return napi_create_threadsafe_function(
+      env, func, async_resource, async_resource_name, max_queue_size,
+      initial_thread_count, thread_finalize_data,
+      ThreadsafeFunctionFinalizeTrampoline, ToPointer(tsfn),
+      call_js ? ThreadsafeFunctionTrampoline : nullptr, result);
 }
 
-napi_async_complete_callback AsyncCompleteCallback(void* caller) {
-  AsyncCompleteCallbackWrap cb{caller};
-  return cb();
+napi_status SetInstanceData(napi_env env, uintptr_t data) {
+  return napi_set_instance_data(env, ToPointer(data),
+                                InstanceDataFinalizeTrampoline, nullptr);
 }
 
-napi_finalize FinalizeCallback(void* caller) {
-  FinalizeCallbackWrap cb{caller};
-  return cb();
+napi_status AddEnvCleanupHook(napi_env env, uintptr_t hook) {
+  return napi_add_env_cleanup_hook(env, EnvCleanupTrampoline, ToPointer(hook));
 }
 
-napi_threadsafe_function_call_js ThreadsafeFunctionCallback(void* caller) {
-  ThreadsafeFunctionCallbackWrap cb{caller};
-  return cb();
+napi_status RemoveEnvCleanupHook(napi_env env, uintptr_t hook) {
+  return napi_remove_env_cleanup_hook(env, EnvCleanupTrampoline,
+                                      ToPointer(hook));
 }
 
+napi_status AddAsyncCleanupHook(napi_env env,
+                                uintptr_t hook,
+                                napi_async_cleanup_hook_handle* remove_handle) {
+  return napi_add_async_cleanup_hook(env, AsyncCleanupTrampoline,
+                                     ToPointer(hook), remove_handle);
+}
 
 #ifdef __cplusplus
 }  // extern "C"
-#endif
\ No newline at end of file
+#endif
+
+// Module entry point
+// Node.js looks for napi_register_module_v1 when it loads the shared library
+// built from the Go program. The exports are initialized by the Go side from
+// the declarations made on napi.Module.
+// NAPI_MODULE_INIT is not used because it also registers the module from a
+// static constructor, which would run in every Go program that links this
+// package, not only in the add-on loaded by Node.js.
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+NAPI_MODULE_EXPORT napi_value NAPI_MODULE_INITIALIZER(napi_env env,
+                                                      napi_value exports) {
+  return InitializeModule(env, exports);
+}
+
+#ifdef __cplusplus
+}  // extern "C"
+#endif
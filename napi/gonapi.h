@@ -1,21 +1,119 @@
 #ifndef GO_NAPI_H
 #define GO_NAPI_H
 
+#include <stdint.h>
 #include <node_api.h>
 
 #ifdef __cplusplus
 extern "C" {
 #endif
 
-extern napi_callback Callback(void* caller);
-extern napi_async_execute_callback AsyncExecuteCallback(void* caller);
-extern napi_async_complete_callback AsyncCompleteCallback(void* caller);
-extern napi_finalize FinalizeCallback(void* caller);
-extern napi_threadsafe_function_call_js ThreadsafeFunctionCallback(void* caller);
-extern napi_value callOnMeGo_cgo(napi_env env, napi_callback_info info);
+// Every Go callback handed to N-API is identified by a handle (see
+// registry.go). The handle travels through the data pointer of the N-API
+// call and is used by the trampolines to dispatch to the right Go callback.
+// Functions, classes and objects with methods release their handles through a
+// finalizer once they are garbage-collected.
+
+extern napi_callback Callback(void);
+extern napi_async_execute_callback AsyncExecuteCallback(void);
+extern napi_async_complete_callback AsyncCompleteCallback(void);
+extern napi_finalize FinalizeCallback(void);
+extern napi_threadsafe_function_call_js ThreadsafeFunctionCallback(void);
+extern napi_finalize ThreadsafeFunctionFinalizeCallback(void);
+
+// AttachHandles releases the handles once the JavaScript object is collected.
+// If it fails, attached receives the number of handles that will still be
+// released with the object.
+extern napi_status AttachHandles(napi_env env,
+                                 napi_value js_object,
+                                 const uintptr_t* handles,
+                                 size_t count,
+                                 size_t* attached);
+
+// SetPropertyCallbacks sets the method, the getter and the setter of the
+// descriptor that are enabled. All of them are dispatched through the same
+// property handle, as a descriptor has a single data pointer.
+extern void SetPropertyCallbacks(napi_property_descriptor* desc,
+                                 uintptr_t property,
+                                 bool method,
+                                 bool getter,
+                                 bool setter);
+
+extern napi_status CreateFunction(napi_env env,
+                                  const char* utf8name,
+                                  size_t length,
+                                  uintptr_t caller,
+                                  napi_value* result);
+
+extern napi_status DefineClass(napi_env env,
+                               const char* utf8name,
+                               size_t length,
+                               uintptr_t constructor,
+                               size_t property_count,
+                               const napi_property_descriptor* properties,
+                               napi_value* result);
+
+// WrapHandle wraps the handle in the JavaScript object and releases it once
+// the object is collected.
+extern napi_status WrapHandle(napi_env env,
+                              napi_value js_object,
+                              uintptr_t wrapped);
+
+extern napi_status AddFinalizer(napi_env env,
+                                napi_value js_object,
+                                void* native_object,
+                                uintptr_t finalizer,
+                                napi_ref* result);
+
+// CreateExternal, CreateExternalArrayBuffer and CreateExternalBuffer create
+// values over external data, calling the finalizer once they are collected.
+extern napi_status CreateExternal(napi_env env,
+                                  void* data,
+                                  uintptr_t finalizer,
+                                  napi_value* result);
+
+extern napi_status CreateExternalArrayBuffer(napi_env env,
+                                             void* external_data,
+                                             size_t byte_length,
+                                             uintptr_t finalizer,
+                                             napi_value* result);
+
+extern napi_status CreateExternalBuffer(napi_env env,
+                                        size_t length,
+                                        void* data,
+                                        uintptr_t finalizer,
+                                        napi_value* result);
+
+extern napi_status CreateAsyncWork(napi_env env,
+                                   napi_value async_resource,
+                                   napi_value async_resource_name,
+                                   uintptr_t work,
+                                   napi_async_work* result);
+
+extern napi_status CreateThreadsafeFunction(napi_env env,
+                                            napi_value func,
+                                            napi_value async_resource,
+                                            napi_value async_resource_name,
+                                            size_t max_queue_size,
+                                            size_t initial_thread_count,
+                                            void* thread_finalize_data,
+                                            uintptr_t tsfn,
+                                            bool call_js,
+                                            napi_threadsafe_function* result);
+
+extern napi_status SetInstanceData(napi_env env, uintptr_t data);
+
+extern napi_status AddEnvCleanupHook(napi_env env, uintptr_t hook);
+
+extern napi_status RemoveEnvCleanupHook(napi_env env, uintptr_t hook);
+
+extern napi_status AddAsyncCleanupHook(
+    napi_env env,
+    uintptr_t hook,
+    napi_async_cleanup_hook_handle* remove_handle);
 
 #ifdef __cplusplus
 }  // extern "C"
 #endif
 
-#endif  // GO_NAPI_H
\ No newline at end of file
+#endif  // GO_NAPI_H
@@ -0,0 +1,336 @@
+package napi
+
+import (
+	"reflect"
+)
+
+// Object, Array and Function
+// Object, Array and Function bind a JavaScript value to its environment, so
+// that it can be used without passing env to every call:
+//  options, status := napi.AsObject(env, args[0])
+//  retries, status := options.Get("retries")
+//  callback, status := napi.AsFunction(env, args[1])
+//  res, err := callback.Call(nil, retries)
+// The type of the value is checked when the handle is created. Handles, like
+// the values they contain, are only valid in the handle scope they have been
+// created in. Functions exported with Export can take handles as parameters
+// and return them, as ToJS and FromJS convert them to and from their values.
+// Functions are called with Go arguments and results, that are converted as
+// described for ToJS and FromJS. An exception thrown by the function is
+// caught and returned as a *JSError:
+//  var total float64
+//  if err := callback.CallInto(&total, nil, 1, "two", []int{3}); err != nil {
+//  	return err // thrown again if returned to JavaScript
+//  }
+
+// Object is a JavaScript object, or function, bound to its environment.
+type Object struct {
+	env   Env
+	value Value
+}
+
+// Array is a JavaScript array bound to its environment.
+type Array struct {
+	Object
+}
+
+// Function is a JavaScript function bound to its environment.
+type Function struct {
+	Object
+}
+
+// AsObject function returns the Object handle of an object or a function.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The JavaScript object.
+// Returns napi_object_expected if value is not an object or a function.
+func AsObject(env Env, value Value) (Object, Status) {
+	vt, status := TypeOf(env, value)
+	if status != Status(Statuses.OK) {
+		return Object{}, status
+	}
+	if vt != ValueType(ValueTypes.Object) && vt != ValueType(ValueTypes.Function) {
+		return Object{}, Status(Statuses.ObjectExpected)
+	}
+	return Object{env: env, value: value}, status
+}
+
+// NewObject function creates an empty JavaScript object.
+// [in] env: The environment that the API is invoked under.
+func NewObject(env Env) (Object, Status) {
+	value, status := CreateObject(env)
+	if status != Status(Statuses.OK) {
+		return Object{}, status
+	}
+	return Object{env: env, value: value}, status
+}
+
+// AsArray function returns the Array handle of an array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The JavaScript array.
+// Returns napi_array_expected if value is not an array.
+func AsArray(env Env, value Value) (Array, Status) {
+	isArray, status := IsArray(env, value)
+	if status != Status(Statuses.OK) {
+		return Array{}, status
+	}
+	if !isArray {
+		return Array{}, Status(Statuses.ArrayExpected)
+	}
+	return Array{Object{env: env, value: value}}, status
+}
+
+// NewArray function creates a JavaScript array containing values.
+// [in] env: The environment that the API is invoked under.
+// [in] values: The elements of the array.
+func NewArray(env Env, values ...Value) (Array, Status) {
+	value, status := CreateArrayWithLength(env, uint(len(values)))
+	if status != Status(Statuses.OK) {
+		return Array{}, status
+	}
+	for i, elem := range values {
+		if status := SetElement(env, value, uint(i), elem); status != Status(Statuses.OK) {
+			return Array{}, status
+		}
+	}
+	return Array{Object{env: env, value: value}}, status
+}
+
+// AsFunction function returns the Function handle of a function.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The JavaScript function.
+// Returns napi_function_expected if value is not a function.
+func AsFunction(env Env, value Value) (Function, Status) {
+	vt, status := TypeOf(env, value)
+	if status != Status(Statuses.OK) {
+		return Function{}, status
+	}
+	if vt != ValueType(ValueTypes.Function) {
+		return Function{}, Status(Statuses.FunctionExpected)
+	}
+	return Function{Object{env: env, value: value}}, status
+}
+
+// Env returns the environment of the object.
+func (o Object) Env() Env {
+	return o.env
+}
+
+// Value returns the JavaScript value of the object.
+func (o Object) Value() Value {
+	return o.value
+}
+
+// Get returns the named property of the object, like object[key].
+func (o Object) Get(key string) (Value, Status) {
+	return GetNamedProperty(o.env, o.value, key)
+}
+
+// Set sets the named property of the object, like object[key] = value.
+func (o Object) Set(key string, value Value) Status {
+	return SetNamedProperty(o.env, o.value, key, value)
+}
+
+// Has reports whether the object, or its prototype chain, has the named
+// property, like key in object.
+func (o Object) Has(key string) (bool, Status) {
+	return HasNamedProperty(o.env, o.value, key)
+}
+
+// Delete deletes the named own property of the object, like
+// delete object[key]. It reports whether the property has been deleted, that
+// is also the case if the object had no such property.
+func (o Object) Delete(key string) (bool, Status) {
+	name, status := CreateStringUtf8(o.env, key)
+	if status != Status(Statuses.OK) {
+		return false, status
+	}
+	return DeleteProperty(o.env, o.value, name)
+}
+
+// Keys returns the names of the own enumerable properties of the object whose
+// keys are strings, like Object.keys(object).
+func (o Object) Keys() ([]string, Status) {
+	names, status := GetAllPropertyNames(o.env, o.value,
+		KeyCollectionMode(KeyCollectionModes.OwnOnly),
+		KeyFilter(KeyFilters.Enumerable|KeyFilters.SkipSymbols),
+		KeyConversion(KeyConversions.NumbersToStrings))
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	length, status := GetArrayLength(o.env, names)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	keys := make([]string, length)
+	for i := range keys {
+		name, status := GetElement(o.env, names, uint(i))
+		if status != Status(Statuses.OK) {
+			return nil, status
+		}
+		if keys[i], status = GetValueStringUtf8(o.env, name); status != Status(Statuses.OK) {
+			return nil, status
+		}
+	}
+	return keys, status
+}
+
+// Freeze freezes the object, like Object.freeze(object).
+func (o Object) Freeze() Status {
+	return ObjectFreeze(o.env, o.value)
+}
+
+// Len returns the length of the array.
+func (a Array) Len() (uint32, Status) {
+	return GetArrayLength(a.env, a.value)
+}
+
+// At returns the element of the array at index, like array[index].
+func (a Array) At(index uint32) (Value, Status) {
+	return GetElement(a.env, a.value, uint(index))
+}
+
+// Push appends values to the array, like array.push(...values).
+func (a Array) Push(values ...Value) Status {
+	length, status := a.Len()
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	for i, value := range values {
+		if status := SetElement(a.env, a.value, uint(length)+uint(i), value); status != Status(Statuses.OK) {
+			return status
+		}
+	}
+	return status
+}
+
+// Call calls the function with this and args, like fn.call(this, ...args),
+// converting the arguments with ToJS and the result to its natural Go
+// representation, as FromJS does for empty interfaces. A nil this is passed as
+// undefined. An exception thrown by the function is returned as a *JSError.
+func (f Function) Call(this Value, args ...interface{}) (interface{}, error) {
+	var res interface{}
+	if err := f.CallInto(&res, this, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CallInto calls the function like Call, and converts the result into the Go
+// value pointed to by result, as FromJS does. A nil result discards it.
+func (f Function) CallInto(result interface{}, this Value, args ...interface{}) error {
+	var rv reflect.Value
+	if result != nil {
+		if rv = reflect.ValueOf(result); rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return conversionError("", "CallInto result must be a non-nil pointer, not %T", result)
+		}
+	}
+	if this == nil {
+		var status Status
+		if this, status = GetUndefined(f.env); status != Status(Statuses.OK) {
+			return CheckStatus(f.env, status)
+		}
+	}
+	values, err := f.args(args)
+	if err != nil {
+		return err
+	}
+	res, status := CallFunction(f.env, this, f.value, values)
+	if status != Status(Statuses.OK) {
+		return callError(f.env, status)
+	}
+	if result == nil {
+		return nil
+	}
+	return fromJS(f.env, res, rv.Elem(), "result")
+}
+
+// args converts the arguments of a call with ToJS.
+func (f Function) args(args []interface{}) ([]Value, error) {
+	values := make([]Value, len(args))
+	for i, arg := range args {
+		var err error
+		if values[i], err = toJS(f.env, reflect.ValueOf(arg), indexPath("args", i)); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// callError returns the error of a call to JavaScript that failed: the
+// exception thrown, or an *Error.
+func callError(env Env, status Status) error {
+	if status == Status(Statuses.PendingException) {
+		if err := CatchException(env); err != nil {
+			return err
+		}
+	}
+	return CheckStatus(env, status)
+}
+
+// New calls the function as a constructor, like new fn(...args), converting
+// the arguments with ToJS. An exception thrown by the constructor is returned
+// as a *JSError.
+func (f Function) New(args ...interface{}) (Object, error) {
+	values, err := f.args(args)
+	if err != nil {
+		return Object{}, err
+	}
+	value, status := NewInstance(f.env, f.value, values)
+	if status != Status(Statuses.OK) {
+		return Object{}, callError(f.env, status)
+	}
+	return Object{env: f.env, value: value}, nil
+}
+
+var (
+	objectType   = reflect.TypeOf(Object{})
+	arrayType    = reflect.TypeOf(Array{})
+	functionType = reflect.TypeOf(Function{})
+)
+
+// handleToJS converts an Object, Array or Function handle to its value.
+func handleToJS(env Env, rv reflect.Value, path string) (Value, error) {
+	var value Value
+	switch h := rv.Interface().(type) {
+	case Object:
+		value = h.value
+	case Array:
+		value = h.value
+	case Function:
+		value = h.value
+	}
+	if value == nil {
+		// The zero handle has no value.
+		res, status := GetUndefined(env)
+		return checkValue(env, res, status, path)
+	}
+	return value, nil
+}
+
+// handleFromJS converts a value to an Object, Array or Function handle,
+// checking its type.
+func handleFromJS(env Env, value Value, rv reflect.Value, path string) error {
+	var res interface{}
+	var status Status
+	var expected string
+	switch rv.Type() {
+	case objectType:
+		res, status = AsObject(env, value)
+		expected = "object"
+	case arrayType:
+		res, status = AsArray(env, value)
+		expected = "array"
+	case functionType:
+		res, status = AsFunction(env, value)
+		expected = "function"
+	}
+	switch status {
+	case Status(Statuses.OK):
+		rv.Set(reflect.ValueOf(res))
+		return nil
+	case Status(Statuses.ObjectExpected), Status(Statuses.ArrayExpected), Status(Statuses.FunctionExpected):
+		return conversionError(path, "expected %s", expected)
+	default:
+		return statusError(env, path, status)
+	}
+}
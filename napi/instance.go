@@ -0,0 +1,174 @@
+package napi
+
+/*
+#include <stdlib.h>
+#include "gonapi.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// Instance data
+// An add-on can be loaded multiple times in the same process, for example by
+// the main thread and by every worker thread. Go global variables are shared
+// by all the environments, so state like cached constructors and references
+// must instead be associated with the environment it belongs to.
+// N-API provides a single instance data slot for every environment. This
+// package uses it to store, through a handle, a Go container that holds the
+// instance data of the add-on and the values of every EnvLocal. The container
+// is released when the environment is torn down, after the finalizers of the
+// values have been called.
+// An EnvLocal is usually declared as a package level variable:
+//  var constructors = &napi.EnvLocal{
+//  	New: func(env napi.Env) interface{} { return &Constructors{} },
+//  }
+// and read from any callback with constructors.Get(env).
+
+// EnvFinalize represents a function called when the environment a value is
+// associated with is torn down.
+type EnvFinalize func(env Env, value interface{})
+
+// EnvLocal is a variable that has a separate value for every environment. It
+// is usually declared as a package level variable.
+type EnvLocal struct {
+	// New optionally creates the value the first time it is read in an
+	// environment.
+	New func(env Env) interface{}
+	// Finalize is optionally called with the value when the environment is
+	// torn down.
+	Finalize EnvFinalize
+}
+
+// Get returns the value of the variable for the environment. If no value has
+// been set yet, it is created with New (or nil if New is not set).
+// [in] env: The environment that the API is invoked under.
+func (l *EnvLocal) Get(env Env) (interface{}, Status) {
+	data, status := getInstanceData(env, true)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if value, ok := data.locals[l]; ok {
+		return value, status
+	}
+	var value interface{}
+	if l.New != nil {
+		value = l.New(env)
+	}
+	data.locals[l] = value
+	return value, status
+}
+
+// Set sets the value of the variable for the environment.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The value associated with the environment.
+func (l *EnvLocal) Set(env Env, value interface{}) Status {
+	data, status := getInstanceData(env, true)
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	data.locals[l] = value
+	return status
+}
+
+// Delete removes the value of the variable for the environment without
+// calling Finalize.
+// [in] env: The environment that the API is invoked under.
+func (l *EnvLocal) Delete(env Env) Status {
+	data, status := getInstanceData(env, false)
+	if data != nil {
+		delete(data.locals, l)
+	}
+	return status
+}
+
+// SetInstanceData function associates data with the currently running
+// environment. The data can be retrieved later with GetInstanceData. Any
+// existing data associated with the environment is overwritten without
+// calling its finalizer, as N-API does.
+// [in] env: The environment that the API is invoked under.
+// [in] data: The data item to make available to bindings of this instance.
+// [in] finalizer: Optional function to call when the environment is being
+// torn down.
+// N-API version: 6
+func SetInstanceData(env Env, data interface{}, finalizer EnvFinalize) Status {
+	instance, status := getInstanceData(env, true)
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	instance.data = data
+	instance.finalize = finalizer
+	return status
+}
+
+// GetInstanceData function retrieves the data that was previously associated
+// with the currently running environment via SetInstanceData. If no data is
+// set, the call will succeed and data will be nil.
+// [in] env: The environment that the API is invoked under.
+// N-API version: 6
+func GetInstanceData(env Env) (interface{}, Status) {
+	instance, status := getInstanceData(env, false)
+	if instance == nil {
+		return nil, status
+	}
+	return instance.data, status
+}
+
+// instanceData is the Go container stored in the instance data slot of an
+// environment.
+type instanceData struct {
+	data     interface{}
+	finalize EnvFinalize
+	locals   map[*EnvLocal]interface{}
+}
+
+// release calls the finalizers of all the values held by the container.
+func (d *instanceData) release(env Env) {
+	for local, value := range d.locals {
+		if local.Finalize != nil {
+			local.Finalize(env, value)
+		}
+	}
+	d.locals = nil
+	if d.finalize != nil {
+		d.finalize(env, d.data)
+	}
+	d.data = nil
+}
+
+// getInstanceData returns the container stored in the instance data slot of
+// the environment, creating it if create is true.
+func getInstanceData(env Env, create bool) (*instanceData, Status) {
+	var res C.uintptr_t
+	var status = C.napi_get_instance_data(env, (*unsafe.Pointer)(unsafe.Pointer(&res)))
+	if status != C.napi_ok {
+		return nil, Status(status)
+	}
+	if entry, ok := callbacks.get(handle(res)); ok {
+		return entry.(*instanceData), Status(status)
+	}
+	if !create {
+		return nil, Status(status)
+	}
+	data := &instanceData{
+		locals: make(map[*EnvLocal]interface{}),
+	}
+	h := callbacks.add(data)
+	status = C.SetInstanceData(env, C.uintptr_t(h))
+	if status != C.napi_ok {
+		callbacks.remove(h)
+		return nil, Status(status)
+	}
+	return data, Status(status)
+}
+
+//export CallInstanceDataFinalizeCallback
+func CallInstanceDataFinalizeCallback(wrap C.uintptr_t, env C.napi_env) {
+	defer recoverFatal(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	defer callbacks.remove(handle(wrap))
+	entry.(*instanceData).release(env)
+}
@@ -0,0 +1,159 @@
+package napi
+
+/*
+#include <node_api.h>
+*/
+import "C"
+import "strings"
+
+// JavaScript exceptions
+// When an N-API call returns napi_pending_exception, the exception thrown by
+// JavaScript can be caught as a Go error:
+//  napi.CallFunction(env, recv, fn, args)
+//  if err := napi.CatchException(env); err != nil {
+//  	var jsErr *napi.JSError
+//  	if errors.As(err, &jsErr) && jsErr.Code == "ENOENT" {
+//  		...
+//  	}
+//  	jsErr.Throw(env) // rethrow the original exception
+//  }
+// The Value of a JSError, like any other Value, is only valid in the handle
+// scope it has been caught in.
+
+// maxCauseDepth limits the chain of causes read from an exception.
+const maxCauseDepth = 16
+
+// JSError is a JavaScript exception converted to a Go error. Any value can be
+// thrown in JavaScript: for values that are not objects, Name, Code and Stack
+// are empty and Message is the value converted to a string.
+type JSError struct {
+	// Name is the name of the error, for example "TypeError".
+	Name string
+	// Message is the message of the error.
+	Message string
+	// Code is the code of the error, as set by Node.js and by many libraries.
+	Code string
+	// Stack is the stack trace of the error, including name and message.
+	Stack string
+	// Cause is the cause of the error, converted to a *JSError, if any.
+	Cause error
+	// Value is the exception object.
+	Value Value
+}
+
+func (e *JSError) Error() string {
+	var parts []string
+	if e.Name != "" {
+		parts = append(parts, e.Name)
+	}
+	if e.Code != "" {
+		parts = append(parts, "["+e.Code+"]")
+	}
+	msg := strings.Join(parts, " ")
+	if msg == "" {
+		return e.Message
+	}
+	if e.Message == "" {
+		return msg
+	}
+	return msg + ": " + e.Message
+}
+
+// Unwrap returns the cause of the error.
+func (e *JSError) Unwrap() error {
+	return e.Cause
+}
+
+// Throw function throws the original exception object again.
+// [in] env: The environment that the API is invoked under.
+func (e *JSError) Throw(env Env) Status {
+	return Throw(env, e.Value)
+}
+
+// CatchException function clears the pending JavaScript exception and returns
+// it as a *JSError. It returns nil if no exception is pending.
+// [in] env: The environment that the API is invoked under.
+func CatchException(env Env) error {
+	pending, status := IsExceptionPending(env)
+	if status != Status(Statuses.OK) || !pending {
+		return nil
+	}
+	exception, status := GetAndClearLastException(env)
+	if status != Status(Statuses.OK) {
+		return CheckStatus(env, status)
+	}
+	return NewJSError(env, exception)
+}
+
+// NewJSError function converts a thrown JavaScript value into a *JSError.
+// [in] env: The environment that the API is invoked under.
+// [in] exception: The thrown value.
+func NewJSError(env Env, exception Value) *JSError {
+	return newJSError(env, exception, nil)
+}
+
+// newJSError converts the exception. effects are the exceptions caused by it,
+// outermost first.
+func newJSError(env Env, exception Value, effects []Value) *JSError {
+	e := &JSError{Value: exception}
+	vt, status := TypeOf(env, exception)
+	if status != Status(Statuses.OK) {
+		return e
+	}
+	if vt != C.napi_object && vt != C.napi_function {
+		e.Message = stringProperty(env, exception, "")
+		return e
+	}
+	e.Name = stringProperty(env, exception, "name")
+	e.Message = stringProperty(env, exception, "message")
+	e.Code = stringProperty(env, exception, "code")
+	e.Stack = stringProperty(env, exception, "stack")
+	if cause, ok := property(env, exception, "cause"); ok && len(effects) < maxCauseDepth {
+		effects = append(effects, exception)
+		// The chain of causes stops when it becomes circular.
+		for _, effect := range effects {
+			if same, _ := StrictEquals(env, cause, effect); same {
+				return e
+			}
+		}
+		e.Cause = newJSError(env, cause, effects)
+	}
+	return e
+}
+
+// property returns the named property of the object, unless it is undefined.
+// A property getter throwing is ignored.
+func property(env Env, object Value, name string) (Value, bool) {
+	res, status := GetNamedProperty(env, object, name)
+	if status != Status(Statuses.OK) {
+		GetAndClearLastException(env)
+		return nil, false
+	}
+	vt, status := TypeOf(env, res)
+	if status != Status(Statuses.OK) || vt == C.napi_undefined {
+		return nil, false
+	}
+	return res, true
+}
+
+// stringProperty returns the named property of the object converted to a
+// string, or the object itself converted to a string if name is empty.
+// Missing properties, and values that cannot be converted, are returned as
+// empty strings.
+func stringProperty(env Env, object Value, name string) string {
+	value := object
+	if name != "" {
+		var ok bool
+		if value, ok = property(env, object, name); !ok {
+			return ""
+		}
+	}
+	str, status := CoerceToString(env, value)
+	if status != Status(Statuses.OK) {
+		// Symbols, and objects whose toString throws, cannot be converted.
+		GetAndClearLastException(env)
+		return ""
+	}
+	res, _ := GetValueStringUtf8(env, str)
+	return res
+}
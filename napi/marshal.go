@@ -0,0 +1,718 @@
+package napi
+
+/*
+#include <stdlib.h>
+#include <node_api.h>
+*/
+import "C"
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Value conversion
+// ToJS and FromJS convert between Go values and JavaScript values using
+// reflection, following rules similar to the ones of encoding/json:
+//  Go                               JavaScript
+//  bool                             boolean
+//  int, uint and float kinds        number
+//  big.Int                          BigInt
+//  time.Time                        Date
+//  string                           string
+//  slice and array                  Array
+//  map with string keys             Object
+//  struct                           Object
+//  pointer                          the value pointed to
+//  nil pointer, slice, map, ...     null
+//  Value                            the value itself
+//  Object, Array and Function       the value of the handle
+// Struct fields are converted if exported. The name of the property can be
+// changed with the `json` field tag, that also supports the "omitempty" option
+// and the "-" name to skip the field. Anonymous struct fields are flattened.
+// When converting to Go, null and undefined set pointers, slices, maps and
+// interfaces to nil, while struct fields with an undefined property are left
+// untouched. An empty interface receives the natural Go representation of the
+// JavaScript value: nil, bool, float64, string, *big.Int, time.Time,
+// []interface{} or map[string]interface{}; other JavaScript values are kept as
+// Value. Integers also accept the BigInts they can hold exactly. Instances of
+// the classes bound with DefineClassFor are converted to the Go value they
+// wrap. Typed arrays are converted to Go slices and arrays like arrays.
+// Object, Array and Function handles only accept values of their type.
+
+// ConversionError describes a value that cannot be converted between Go and
+// JavaScript. Path locates the value, for example "items[3].id". Err is the
+// *Error of the N-API call that failed during the conversion, if any.
+type ConversionError struct {
+	Path string
+	Msg  string
+	Err  error
+}
+
+func (e *ConversionError) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return e.Path + ": " + e.Msg
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// conversionError returns a ConversionError for the value at path.
+func conversionError(path string, format string, args ...interface{}) error {
+	return &ConversionError{Path: path, Msg: fmt.Sprintf(format, args...)}
+}
+
+// statusError returns the error for an N-API call that failed while
+// converting the value at path.
+func statusError(env Env, path string, status Status) error {
+	err := CheckStatus(env, status)
+	return &ConversionError{Path: path, Msg: err.Error(), Err: err}
+}
+
+// fieldPath returns the path of the named property of the value at path.
+func fieldPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// indexPath returns the path of the element of the value at path.
+func indexPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+var valueType = reflect.TypeOf(Value(nil))
+
+// ToJS function converts a Go value into a JavaScript value.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Go value to convert.
+func ToJS(env Env, value interface{}) (Value, error) {
+	return toJS(env, reflect.ValueOf(value), "")
+}
+
+// FromJS function converts a JavaScript value into the Go value pointed to by
+// target.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The JavaScript value to convert.
+// [in] target: A non-nil pointer to the Go value that receives the result.
+func FromJS(env Env, value Value, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return conversionError("", "FromJS target must be a non-nil pointer, not %T", target)
+	}
+	return fromJS(env, value, rv.Elem(), "")
+}
+
+// maxDepth is the maximum nesting of the Go values converted by ToJS, that
+// stops the conversion of cyclic values, like a struct pointing to itself.
+const maxDepth = 1000
+
+func toJS(env Env, rv reflect.Value, path string) (Value, error) {
+	return nestedToJS(env, rv, path, 0)
+}
+
+// nestedToJS converts the value found at depth in the value being converted.
+func nestedToJS(env Env, rv reflect.Value, path string, depth int) (Value, error) {
+	var res Value
+	var status Status
+	if !rv.IsValid() {
+		res, status = GetNull(env)
+		return checkValue(env, res, status, path)
+	}
+	if rv.Type() == valueType {
+		return rv.Interface().(Value), nil
+	}
+	switch rv.Type() {
+	case bigIntType:
+		return bigIntToJS(env, rv, path)
+	case timeType:
+		return timeToJS(env, rv, path)
+	case objectType, arrayType, functionType:
+		return handleToJS(env, rv, path)
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if depth >= maxDepth {
+			return nil, conversionError(path, "value of type %s is cyclic or nested too deeply", rv.Type())
+		}
+		depth++
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		res, status = GetBoolean(env, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		res, status = CreateInt64(env, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		res, status = CreateDouble(env, float64(rv.Uint()))
+	case reflect.Float32, reflect.Float64:
+		res, status = CreateDouble(env, rv.Float())
+	case reflect.String:
+		res, status = CreateStringUtf8(env, rv.String())
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			res, status = GetNull(env)
+			break
+		}
+		return nestedToJS(env, rv.Elem(), path, depth)
+	case reflect.Slice:
+		if rv.IsNil() {
+			res, status = GetNull(env)
+			break
+		}
+		return arrayToJS(env, rv, path, depth)
+	case reflect.Array:
+		return arrayToJS(env, rv, path, depth)
+	case reflect.Map:
+		if rv.IsNil() {
+			res, status = GetNull(env)
+			break
+		}
+		return mapToJS(env, rv, path, depth)
+	case reflect.Struct:
+		return structToJS(env, rv, path, depth)
+	default:
+		return nil, conversionError(path, "unsupported Go type %s", rv.Type())
+	}
+	return checkValue(env, res, status, path)
+}
+
+func checkValue(env Env, res Value, status Status, path string) (Value, error) {
+	if status != Status(Statuses.OK) {
+		return nil, statusError(env, path, status)
+	}
+	return res, nil
+}
+
+func arrayToJS(env Env, rv reflect.Value, path string, depth int) (Value, error) {
+	res, status := CreateArrayWithLength(env, uint(rv.Len()))
+	if status != Status(Statuses.OK) {
+		return nil, statusError(env, path, status)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		elem, err := nestedToJS(env, rv.Index(i), indexPath(path, i), depth)
+		if err != nil {
+			return nil, err
+		}
+		if status := SetElement(env, res, uint(i), elem); status != Status(Statuses.OK) {
+			return nil, statusError(env, indexPath(path, i), status)
+		}
+	}
+	return res, nil
+}
+
+func mapToJS(env Env, rv reflect.Value, path string, depth int) (Value, error) {
+	if rv.Type().Key().Kind() != reflect.String {
+		return nil, conversionError(path, "unsupported map key type %s", rv.Type().Key())
+	}
+	res, status := CreateObject(env)
+	if status != Status(Statuses.OK) {
+		return nil, statusError(env, path, status)
+	}
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	for _, key := range keys {
+		name := key.String()
+		elem, err := nestedToJS(env, rv.MapIndex(key), fieldPath(path, name), depth)
+		if err != nil {
+			return nil, err
+		}
+		if status := SetNamedProperty(env, res, name, elem); status != Status(Statuses.OK) {
+			return nil, statusError(env, fieldPath(path, name), status)
+		}
+	}
+	return res, nil
+}
+
+func structToJS(env Env, rv reflect.Value, path string, depth int) (Value, error) {
+	res, status := CreateObject(env)
+	if status != Status(Statuses.OK) {
+		return nil, statusError(env, path, status)
+	}
+	for _, field := range cachedFields(rv.Type()) {
+		fv, ok := fieldByIndex(rv, field.index, false)
+		if !ok || (field.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		elem, err := nestedToJS(env, fv, fieldPath(path, field.name), depth)
+		if err != nil {
+			return nil, err
+		}
+		if status := SetNamedProperty(env, res, field.name, elem); status != Status(Statuses.OK) {
+			return nil, statusError(env, fieldPath(path, field.name), status)
+		}
+	}
+	return res, nil
+}
+
+func fromJS(env Env, value Value, rv reflect.Value, path string) error {
+	if rv.Type() == valueType {
+		rv.Set(reflect.ValueOf(value))
+		return nil
+	}
+	vt, status := TypeOf(env, value)
+	if status != Status(Statuses.OK) {
+		return statusError(env, path, status)
+	}
+	return typedFromJS(env, value, vt, rv, path)
+}
+
+// typedFromJS converts value, whose type vt is already known, into rv.
+func typedFromJS(env Env, value Value, vt ValueType, rv reflect.Value, path string) error {
+	if rv.Type() == valueType {
+		rv.Set(reflect.ValueOf(value))
+		return nil
+	}
+	if vt == C.napi_undefined || vt == C.napi_null {
+		switch rv.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+	}
+	if vt == C.napi_object && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		// Objects created by a class bound with DefineClassFor are converted to
+		// the Go value they wrap.
+		if wrapped, ok := unwrapValue(env, value); ok && wrapped.Type().AssignableTo(rv.Type()) {
+			rv.Set(wrapped)
+			return nil
+		}
+	}
+	switch rv.Type() {
+	case bigIntType:
+		return bigIntFromJS(env, value, vt, rv, path)
+	case timeType:
+		return timeFromJS(env, value, rv, path)
+	case objectType, arrayType, functionType:
+		return handleFromJS(env, value, rv, path)
+	}
+	if vt == C.napi_bigint {
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return bigIntegerFromJS(env, value, rv, path)
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		if vt != C.napi_boolean {
+			return conversionError(path, "expected boolean")
+		}
+		b, status := GetValueBool(env, value)
+		if status != Status(Statuses.OK) {
+			return statusError(env, path, status)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, err := numberFromJS(env, value, vt, path)
+		if err != nil {
+			return err
+		}
+		i := int64(f)
+		if float64(i) != f {
+			return conversionError(path, "expected integer")
+		}
+		if rv.OverflowInt(i) {
+			return conversionError(path, "number %v overflows %s", f, rv.Type())
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f, err := numberFromJS(env, value, vt, path)
+		if err != nil {
+			return err
+		}
+		u := uint64(f)
+		if f < 0 || float64(u) != f {
+			return conversionError(path, "expected unsigned integer")
+		}
+		if rv.OverflowUint(u) {
+			return conversionError(path, "number %v overflows %s", f, rv.Type())
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := numberFromJS(env, value, vt, path)
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	case reflect.String:
+		if vt != C.napi_string {
+			return conversionError(path, "expected string")
+		}
+		s, status := GetValueStringUtf8(env, value)
+		if status != Status(Statuses.OK) {
+			return statusError(env, path, status)
+		}
+		rv.SetString(s)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return fromJS(env, value, rv.Elem(), path)
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return conversionError(path, "unsupported Go type %s", rv.Type())
+		}
+		res, err := anyFromJS(env, value, vt, path)
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(res))
+		}
+	case reflect.Slice, reflect.Array:
+		return arrayFromJS(env, value, rv, path)
+	case reflect.Map:
+		if vt != C.napi_object {
+			return conversionError(path, "expected object")
+		}
+		return mapFromJS(env, value, rv, path)
+	case reflect.Struct:
+		if vt != C.napi_object {
+			return conversionError(path, "expected object")
+		}
+		return structFromJS(env, value, rv, path)
+	default:
+		return conversionError(path, "unsupported Go type %s", rv.Type())
+	}
+	return nil
+}
+
+func numberFromJS(env Env, value Value, vt ValueType, path string) (float64, error) {
+	if vt != C.napi_number {
+		return 0, conversionError(path, "expected number")
+	}
+	f, status := GetValueDouble(env, value)
+	if status != Status(Statuses.OK) {
+		return 0, statusError(env, path, status)
+	}
+	return f, nil
+}
+
+func anyFromJS(env Env, value Value, vt ValueType, path string) (interface{}, error) {
+	switch vt {
+	case C.napi_undefined, C.napi_null:
+		return nil, nil
+	case C.napi_boolean:
+		var b bool
+		err := fromJS(env, value, reflect.ValueOf(&b).Elem(), path)
+		return b, err
+	case C.napi_number:
+		return numberFromJS(env, value, vt, path)
+	case C.napi_string:
+		var s string
+		err := fromJS(env, value, reflect.ValueOf(&s).Elem(), path)
+		return s, err
+	case C.napi_bigint:
+		var x big.Int
+		err := bigIntFromJS(env, value, vt, reflect.ValueOf(&x).Elem(), path)
+		return &x, err
+	case C.napi_object:
+		isArray, status := IsArray(env, value)
+		if status != Status(Statuses.OK) {
+			return nil, statusError(env, path, status)
+		}
+		if isArray {
+			var a []interface{}
+			err := arrayFromJS(env, value, reflect.ValueOf(&a).Elem(), path)
+			return a, err
+		}
+		isDate, status := IsDate(env, value)
+		if status != Status(Statuses.OK) {
+			return nil, statusError(env, path, status)
+		}
+		if isDate {
+			var t time.Time
+			err := timeFromJS(env, value, reflect.ValueOf(&t).Elem(), path)
+			return t, err
+		}
+		m := make(map[string]interface{})
+		err := mapFromJS(env, value, reflect.ValueOf(&m).Elem(), path)
+		return m, err
+	default:
+		return value, nil
+	}
+}
+
+func arrayFromJS(env Env, value Value, rv reflect.Value, path string) error {
+	isArray, status := IsArray(env, value)
+	if status != Status(Statuses.OK) {
+		return statusError(env, path, status)
+	}
+	var length uint
+	if isArray {
+		var arrayLength uint32
+		arrayLength, status = GetArrayLength(env, value)
+		length = uint(arrayLength)
+	} else {
+		var isTypedArray, copied bool
+		isTypedArray, status = IsTypedArray(env, value)
+		if status != Status(Statuses.OK) {
+			return statusError(env, path, status)
+		}
+		if !isTypedArray {
+			return conversionError(path, "expected array")
+		}
+		if length, copied, status = copyTypedArray(env, value, rv); copied {
+			return nil
+		}
+	}
+	if status != Status(Statuses.OK) {
+		return statusError(env, path, status)
+	}
+	n := int(length)
+	if rv.Kind() == reflect.Slice {
+		rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if i >= n {
+			rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+			continue
+		}
+		elem, status := GetElement(env, value, uint(i))
+		if status != Status(Statuses.OK) {
+			return statusError(env, indexPath(path, i), status)
+		}
+		if err := fromJS(env, elem, rv.Index(i), indexPath(path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapFromJS(env Env, value Value, rv reflect.Value, path string) error {
+	if rv.Type().Key().Kind() != reflect.String {
+		return conversionError(path, "unsupported map key type %s", rv.Type().Key())
+	}
+	names, status := GetPropertyNames(env, value)
+	if status != Status(Statuses.OK) {
+		return statusError(env, path, status)
+	}
+	var keys []string
+	if err := arrayFromJS(env, names, reflect.ValueOf(&keys).Elem(), path); err != nil {
+		return err
+	}
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(rv.Type(), len(keys)))
+	}
+	for _, key := range keys {
+		prop, status := GetNamedProperty(env, value, key)
+		if status != Status(Statuses.OK) {
+			return statusError(env, fieldPath(path, key), status)
+		}
+		elem := reflect.New(rv.Type().Elem()).Elem()
+		if err := fromJS(env, prop, elem, fieldPath(path, key)); err != nil {
+			return err
+		}
+		rv.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), elem)
+	}
+	return nil
+}
+
+func structFromJS(env Env, value Value, rv reflect.Value, path string) error {
+	for _, field := range cachedFields(rv.Type()) {
+		prop, status := GetNamedProperty(env, value, field.name)
+		if status != Status(Statuses.OK) {
+			return statusError(env, fieldPath(path, field.name), status)
+		}
+		vt, status := TypeOf(env, prop)
+		if status != Status(Statuses.OK) {
+			return statusError(env, fieldPath(path, field.name), status)
+		}
+		if vt == C.napi_undefined {
+			continue
+		}
+		fv, ok := fieldByIndex(rv, field.index, true)
+		if !ok {
+			return conversionError(fieldPath(path, field.name), "cannot set embedded pointer to unexported struct %s", embeddedType(rv.Type(), field.index))
+		}
+		if err := fromJS(env, prop, fv, fieldPath(path, field.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structField describes a struct field converted to a JavaScript property.
+type structField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]structField
+
+// cachedFields returns the fields of the struct type converted to JavaScript
+// properties.
+func cachedFields(t reflect.Type) []structField {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields, _ := fieldCache.LoadOrStore(t, dominantFields(typeFields(t, nil, nil)))
+	return fields.([]structField)
+}
+
+// typeFields returns the fields of the struct type, flattening the anonymous
+// struct fields, including the fields that are hidden by others with the same
+// name. parents are the structs embedding t.
+func typeFields(t reflect.Type, index []int, parents []reflect.Type) []structField {
+	var fields []structField
+	var embedded []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, opts = tag[:comma], tag[comma+1:]
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			// A struct embedding itself, through pointers, is only
+			// flattened once.
+			if !containsType(parents, ft) {
+				embedded = append(embedded, typeFields(ft, fieldIndex, append(parents, t))...)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = sf.Name
+		}
+		fields = append(fields, structField{
+			name:      name,
+			index:     fieldIndex,
+			typ:       sf.Type,
+			tagged:    tagged,
+			omitEmpty: hasOption(opts, "omitempty"),
+		})
+	}
+	return append(fields, embedded...)
+}
+
+// dominantFields keeps the fields that dominate the other fields with the same
+// name, as encoding/json does: the least nested field, or among fields nested
+// as deep the only tagged one. The fields of a name that has no dominant field
+// are all dropped.
+func dominantFields(fields []structField) []structField {
+	var res []structField
+	for i, field := range fields {
+		dominant := true
+		for j, other := range fields {
+			if i == j || other.name != field.name {
+				continue
+			}
+			if len(other.index) < len(field.index) ||
+				len(other.index) == len(field.index) && (other.tagged || !field.tagged) {
+				dominant = false
+				break
+			}
+		}
+		if dominant {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+func hasOption(opts string, option string) bool {
+	for opts != "" {
+		var opt string
+		if comma := strings.Index(opts, ","); comma >= 0 {
+			opt, opts = opts[:comma], opts[comma+1:]
+		} else {
+			opt, opts = opts, ""
+		}
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByIndex returns the nested field of the struct. Nil embedded pointers
+// are allocated if alloc is true, otherwise the field is reported as missing.
+// Nil pointers to unexported structs cannot be allocated, like with
+// encoding/json, and the field is also reported as missing.
+func fieldByIndex(rv reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !alloc || !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// containsType reports whether t is one of types.
+func containsType(types []reflect.Type, t reflect.Type) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// embeddedType returns the unexported struct embedded by pointer on the path
+// to the nested field.
+func embeddedType(t reflect.Type, index []int) reflect.Type {
+	for _, x := range index[:len(index)-1] {
+		sf := t.Field(x)
+		t = sf.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+			if sf.PkgPath != "" {
+				break
+			}
+		}
+	}
+	return t
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+	return false
+}
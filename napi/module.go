@@ -0,0 +1,163 @@
+package napi
+
+/*
+#include <node_api.h>
+*/
+import "C"
+import (
+	"sync"
+)
+
+// Module registration
+// Node.js loads an add-on by calling the napi_register_module_v1 entry point
+// exported by the shared library. The entry point is emitted by this package
+// (see gonapi.cc), so a Go program built with -buildmode=c-shared can be loaded
+// with require() without writing any C code. Go packages declare the exports of
+// the add-on on Module, usually from their init function:
+//  func init() {
+//  	napi.Module.Function("hello", hello)
+//  }
+// The declarations are applied to the exports object every time Node.js
+// initializes the add-on for an environment (the main thread or a worker).
+
+// ModuleInit represents a function that initializes the exports of the
+// add-on. It receives the exports object and can return a different value to
+// be used as exports, or nil to keep the received one.
+type ModuleInit func(env Env, exports Value) Value
+
+// This is a struct used as container for the declarations of the add-on
+// exports.
+type module struct {
+	mu         sync.Mutex
+	properties []Property
+	inits      []ModuleInit
+}
+
+// Module contains the declarations of the add-on exports. Functions and
+// properties are defined on the exports object first, then the namespaces
+// registered with RegisterExports are added and finally the initializers are
+// called in the same order in which they were declared.
+var Module = &module{}
+
+// Function declares a function exported by the add-on.
+// [in] name: The name of the exported function.
+// [in] cb: The native function which should be called when the exported
+// function is invoked.
+func (m *module) Function(name string, cb CCallback) {
+	m.Define(Property{
+		Name:   name,
+		Method: &Caller{Cb: cb},
+	})
+}
+
+// Export declares a Go function exported by the add-on, converting its
+// arguments and results as described for Export.
+// [in] name: The name of the exported function.
+// [in] fn: The Go function called when the exported function is invoked.
+func (m *module) Export(name string, fn interface{}) {
+	m.Function(name, reflectCallback(name, fn))
+}
+
+// Class declares a class exported by the add-on, bound to the Go type
+// returned by constructor as described for DefineClassFor.
+// [in] name: The name of the exported class.
+// [in] constructor: The Go function that creates the instances.
+func (m *module) Class(name string, constructor interface{}) {
+	class := newClassType(name, constructor)
+	m.Init(func(env Env, exports Value) Value {
+		res, status := class.define(env)
+		if status == Status(Statuses.OK) {
+			status = SetNamedProperty(env, exports, name, res)
+		}
+		if status != Status(Statuses.OK) {
+			ThrowError(env, "failed to define the class "+name, "")
+		}
+		return nil
+	})
+}
+
+// ErrorClass declares an error class exported by the add-on, used to throw
+// the errors having the same type as example (see RegisterErrorClass).
+// [in] example: A value of the Go error type.
+// [in] class: The class of the JavaScript errors.
+func (m *module) ErrorClass(example error, class *ErrorClass) {
+	RegisterErrorClass(example, class)
+	m.Init(func(env Env, exports Value) Value {
+		res, status := class.Constructor(env)
+		if status == Status(Statuses.OK) {
+			status = SetNamedProperty(env, exports, class.Name, res)
+		}
+		if status != Status(Statuses.OK) {
+			ThrowError(env, "failed to define the error class "+class.Name, "")
+		}
+		return nil
+	})
+}
+
+// Define declares properties defined on the exports of the add-on.
+// [in] properties: The properties to define on the exports object.
+func (m *module) Define(properties ...Property) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.properties = append(m.properties, properties...)
+}
+
+// Init declares a function called to initialize the exports of the add-on.
+// [in] fn: The function that initializes the exports.
+func (m *module) Init(fn ModuleInit) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inits = append(m.inits, fn)
+}
+
+// initialize applies the declarations to the exports object.
+func (m *module) initialize(env Env, exports Value) Value {
+	m.mu.Lock()
+	properties := append([]Property(nil), m.properties...)
+	inits := append([]ModuleInit(nil), m.inits...)
+	m.mu.Unlock()
+	if len(properties) > 0 {
+		if status := DefineProperties(env, exports, properties); status != Status(Statuses.OK) {
+			ThrowError(env, "failed to define the exports of the add-on", "")
+			return nil
+		}
+	}
+	used := make([]string, 0, len(properties))
+	for i := range properties {
+		if name, ok := propertyName(env, &properties[i]); ok {
+			used = append(used, name)
+		}
+	}
+	if !exportsNamespaces.merge(env, exports, used) {
+		if pending, _ := IsExceptionPending(env); !pending {
+			ThrowError(env, "failed to add the exports namespaces of the add-on", "")
+		}
+		return nil
+	}
+	for _, init := range inits {
+		if res := init(env, exports); res != nil {
+			exports = res
+		}
+	}
+	return exports
+}
+
+// propertyName returns the name of the property, that is the string form of
+// its Key if it is set. Properties keyed by a symbol have no name.
+func propertyName(env Env, prop *Property) (string, bool) {
+	if prop.Key == nil {
+		return prop.Name, true
+	}
+	vt, status := TypeOf(env, prop.Key)
+	if status != Status(Statuses.OK) || vt != ValueType(ValueTypes.String) {
+		return "", false
+	}
+	name, status := GetValueStringUtf8(env, prop.Key)
+	return name, status == Status(Statuses.OK)
+}
+
+//export InitializeModule
+func InitializeModule(env C.napi_env, exports C.napi_value) (res C.napi_value) {
+	defer recoverThrow(env)
+	return Module.initialize(env, exports)
+}
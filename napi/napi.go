@@ -16,20 +16,11 @@ package napi
 */
 import "C"
 import (
-	"bytes"
-	"fmt"
-	"time"
+	"runtime/debug"
+	"sync"
 	"unsafe"
 )
 
-// ccstring function transforms a Go string into a C string (array of characters)
-// and returns the pointer to the first element.
-func cstring(s string) unsafe.Pointer {
-	p := make([]byte, len(s)+1)
-	copy(p, s)
-	return unsafe.Pointer(&p[0])
-}
-
 // Aliases for JavaScript types
 // Basic N-API Data Types
 // N-API exposes the following fundamental datatypes as abstractions that are
@@ -239,6 +230,8 @@ type statuses struct {
 	Closing               int
 	BigintExpected        int
 	DateExpected          int
+	ArrayBufferExpected   int
+	DetachableExpected    int
 }
 
 // Statuses contains the status code indicating the success or failure of
@@ -262,6 +255,8 @@ type statuses struct {
 //  napi_closing
 //  napi_bigint_expected
 //  napi_date_expected
+//  napi_arraybuffer_expected
+//  napi_detachable_arraybuffer_expected
 // If additional information is required upon an API returning a failed status,
 // it can be obtained by calling NapiGetLastErrorInfo.
 var Statuses = &statuses{
@@ -284,6 +279,8 @@ var Statuses = &statuses{
 	Closing:               C.napi_closing,
 	BigintExpected:        C.napi_bigint_expected,
 	DateExpected:          C.napi_date_expected,
+	ArrayBufferExpected:   C.napi_arraybuffer_expected,
+	DetachableExpected:    C.napi_detachable_arraybuffer_expected,
 }
 
 // Status represent the status code indicating the success or failure of
@@ -306,9 +303,13 @@ var Statuses = &statuses{
 //  napi_queue_full
 //  napi_closing
 //  napi_bigint_expected
+//  napi_date_expected
+//  napi_arraybuffer_expected
+//  napi_detachable_arraybuffer_expected
 // If additional information is required upon an API returning a failed status,
-// it can be obtained by calling NapiGetLastErrorInfo.
-type Status = C.napi_status
+// it can be obtained by calling NapiGetLastErrorInfo, or by converting the
+// status to an *Error with CheckStatus.
+type Status C.napi_status
 
 // Callback represents a function pointer type for user-provided native
 // functions which are to be exposed to JavaScript via N-API. Callback functions
@@ -413,6 +414,70 @@ var TsfnCallMode = &tsfnCallMode{
 // is full.
 type ThreadsafeFunctionCallMode = C.napi_threadsafe_function_call_mode
 
+// This is a struct used as container for the modes of collecting the keys of
+// an object.
+type keyCollectionModes struct {
+	IncludePrototypes int
+	OwnOnly           int
+}
+
+// KeyCollectionModes contains the values given to NapiGetAllPropertyNames() to
+// indicate whether the keys of the prototype chain are included as well
+// (IncludePrototypes) or only the own keys of the object (OwnOnly).
+var KeyCollectionModes = &keyCollectionModes{
+	IncludePrototypes: C.napi_key_include_prototypes,
+	OwnOnly:           C.napi_key_own_only,
+}
+
+// KeyCollectionMode represents whether the keys of the prototype chain are
+// collected by NapiGetAllPropertyNames().
+type KeyCollectionMode = C.napi_key_collection_mode
+
+// This is a struct used as container for the filters of the keys of an
+// object.
+type keyFilters struct {
+	AllProperties int
+	Writable      int
+	Enumerable    int
+	Configurable  int
+	SkipStrings   int
+	SkipSymbols   int
+}
+
+// KeyFilters contains the bitflags given to NapiGetAllPropertyNames() to
+// select the properties whose keys are collected. They can be combined.
+var KeyFilters = &keyFilters{
+	AllProperties: C.napi_key_all_properties,
+	Writable:      C.napi_key_writable,
+	Enumerable:    C.napi_key_enumerable,
+	Configurable:  C.napi_key_configurable,
+	SkipStrings:   C.napi_key_skip_strings,
+	SkipSymbols:   C.napi_key_skip_symbols,
+}
+
+// KeyFilter represents the properties whose keys are collected by
+// NapiGetAllPropertyNames().
+type KeyFilter = C.napi_key_filter
+
+// This is a struct used as container for the conversions of the keys of an
+// object.
+type keyConversions struct {
+	KeepNumbers      int
+	NumbersToStrings int
+}
+
+// KeyConversions contains the values given to NapiGetAllPropertyNames() to
+// indicate whether the integer indices are returned as numbers (KeepNumbers)
+// or converted to strings (NumbersToStrings).
+var KeyConversions = &keyConversions{
+	KeepNumbers:      C.napi_key_keep_numbers,
+	NumbersToStrings: C.napi_key_numbers_to_strings,
+}
+
+// KeyConversion represents how the integer indices are returned by
+// NapiGetAllPropertyNames().
+type KeyConversion = C.napi_key_conversion
+
 // AsyncExecuteCallback is a function pointer used with functions that
 // support asynchronous operations. Callback functions must statisfy the
 // following signature:
@@ -546,8 +611,11 @@ func Throw(env Env, error Value) Status {
 func ThrowError(env Env, msg string, code string) Status {
 	cmsg := C.CString(msg)
 	defer C.free(unsafe.Pointer(cmsg))
-	var ccode = C.CString(code)
-	defer C.free(unsafe.Pointer(ccode))
+	var ccode *C.char
+	if code != "" {
+		ccode = C.CString(code)
+		defer C.free(unsafe.Pointer(ccode))
+	}
 	return Status(C.napi_throw_error(env, ccode, cmsg))
 }
 
@@ -560,8 +628,11 @@ func ThrowError(env Env, msg string, code string) Status {
 func ThrowTypeError(env Env, msg string, code string) Status {
 	cmsg := C.CString(msg)
 	defer C.free(unsafe.Pointer(cmsg))
-	var ccode = C.CString(code)
-	defer C.free(unsafe.Pointer(ccode))
+	var ccode *C.char
+	if code != "" {
+		ccode = C.CString(code)
+		defer C.free(unsafe.Pointer(ccode))
+	}
 	return Status(C.napi_throw_type_error(env, ccode, cmsg))
 }
 
@@ -574,8 +645,11 @@ func ThrowTypeError(env Env, msg string, code string) Status {
 func ThrowRangError(env Env, msg string, code string) Status {
 	cmsg := C.CString(msg)
 	defer C.free(unsafe.Pointer(cmsg))
-	var ccode = C.CString(code)
-	defer C.free(unsafe.Pointer(ccode))
+	var ccode *C.char
+	if code != "" {
+		ccode = C.CString(code)
+		defer C.free(unsafe.Pointer(ccode))
+	}
 	return Status(C.napi_throw_range_error(env, ccode, cmsg))
 }
 
@@ -841,20 +915,6 @@ func GetReferenceValue(env Env, ref Ref) (Value, Status) {
 	return Value(res), Status(status)
 }
 
-// AddEnvCleanupHook function ...
-func AddEnvCleanupHook(env Env) (Value, Status) {
-	var res C.napi_value
-	var status = C.napi_ok
-	return Value(res), Status(status)
-}
-
-// RemoveCleaupHook function ...
-func RemoveCleaupHook(env Env) (Value, Status) {
-	var res C.napi_value
-	var status = C.napi_ok
-	return Value(res), Status(status)
-}
-
 // CreateArray function returns an N-API value corresponding to a JavaScript
 // Array type. JavaScript arrays are described in Section 22.1 of the ECMAScript
 // Language Specification.
@@ -953,10 +1013,13 @@ func CreateBufferCopy(env Env, length uint, raw unsafe.Pointer) (Value, unsafe.P
 // The created value is not an object, and therefore does not support additional
 // properties. It is considered a distinct value type `napi_external`.
 // N-API version: 1
-func CreateExternal(env Env, raw unsafe.Pointer) (Value, Status) {
+func CreateExternal(env Env, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, Status) {
 	var res C.napi_value
-	// Remember to handle napi_finalize finalize_cb and void* finalize_hint
-	var status = C.napi_create_external(env, raw, nil, nil, &res)
+	var h = callbacks.add(&finalizeEntry{caller: finalizer, hint: hint})
+	var status = C.CreateExternal(env, raw, C.uintptr_t(h), &res)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+	}
 	return Value(res), Status(status)
 }
 
@@ -975,10 +1038,13 @@ func CreateExternal(env Env, raw unsafe.Pointer) (Value, Status) {
 // JavaScript ArrayBuffers are described in Section 24.1 of the ECMAScript
 // Language Specification.
 // N-API version: 1
-func CreateExternalArrayBuffer(env Env, length uint, raw unsafe.Pointer) (Value, Status) {
+func CreateExternalArrayBuffer(env Env, length uint, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, Status) {
 	var res C.napi_value
-	// Remember to handle napi_finalize finalize_cb and void* finalize_hint
-	var status = C.napi_create_external_arraybuffer(env, raw, C.size_t(length), nil, nil, &res)
+	var h = callbacks.add(&finalizeEntry{caller: finalizer, hint: hint})
+	var status = C.CreateExternalArrayBuffer(env, raw, C.size_t(length), C.uintptr_t(h), &res)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+	}
 	return Value(res), Status(status)
 }
 
@@ -996,10 +1062,13 @@ func CreateExternalArrayBuffer(env Env, length uint, raw unsafe.Pointer) (Value,
 // [out] result: A napi_value representing a node::Buffer.
 // Remember that fsor Node.js >=4 Buffers are Uint8Array.
 //  N-API version: 1
-func CreateExternalBuffer(env Env, length uint, raw unsafe.Pointer) (Value, Status) {
+func CreateExternalBuffer(env Env, length uint, raw unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Value, Status) {
 	var res C.napi_value
-	// Remember to handle napi_finalize finalize_cb and void* finalize_hint
-	var status = C.napi_create_external_buffer(env, C.size_t(length), raw, nil, nil, &res)
+	var h = callbacks.add(&finalizeEntry{caller: finalizer, hint: hint})
+	var status = C.CreateExternalBuffer(env, C.size_t(length), raw, C.uintptr_t(h), &res)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+	}
 	return Value(res), Status(status)
 }
 
@@ -1077,6 +1146,21 @@ func CreateDataview(env Env, length uint, offset uint, value Value) (Value, Stat
 	return Value(res), Status(status)
 }
 
+// CreateDate function allocates a JavaScript Date object.
+// JavaScript Date objects are described in Section 20.3 of the ECMAScript
+// Language Specification.
+// [in] env: The environment that the API is invoked under.
+// [in] time: ECMAScript time value in milliseconds since 01 January, 1970 UTC.
+// [out] result: A napi_value representing a JavaScript Date.
+// This API does not observe leap seconds; they are ignored, as ECMAScript
+// aligns with POSIX time specification.
+// N-API version: 5
+func CreateDate(env Env, time float64) (Value, Status) {
+	var res C.napi_value
+	var status = C.napi_create_date(env, C.double(time), &res)
+	return Value(res), Status(status)
+}
+
 // CreateInt32 function creates JavaScript Number from the C int32_t type.
 // [in] env: The environment that the API is invoked under.
 // [in] value: Integer value to be represented in JavaScript.
@@ -1156,57 +1240,79 @@ func CreateBigintUInt64(env Env, value uint64) (Value, Status) {
 // [in] words: An array of uint64_t little-endian 64-bit words.
 // [out] result: A napi_value representing a JavaScript BigInt.
 // Returns napi_ok if the API succeeded.
+// If the resulting BigInt is too large, an Error is thrown and
+// napi_pending_exception is returned.
 // N-API version: -
 func CreateBigintWords(env Env, sign int, words []uint64) (Value, Status) {
 	var res C.napi_value
-	var raw = (unsafe.Pointer(&words[0]))
-	defer C.free(raw)
-	var status = C.napi_create_bigint_words(env, C.int(sign), C.size_t(len(words)), (*C.uint64_t)(raw), &res)
+	// N-API requires a words array even when it is empty.
+	var raw = [1]C.uint64_t{}
+	var ptr = &raw[0]
+	if len(words) > 0 {
+		ptr = (*C.uint64_t)(unsafe.Pointer(&words[0]))
+	}
+	var status = C.napi_create_bigint_words(env, C.int(sign), C.size_t(len(words)), ptr, &res)
 	return Value(res), Status(status)
 }
 
-// CreateStringLatin1 function creates a JavaScript String object from an
-// ISO-8859-1-encoded C string. The native string is copied.
+// CreateStringLatin1 function creates a JavaScript String object from a Go
+// string, encoded in ISO-8859-1 before passing it to N-API. The string is
+// copied.
 // [in] env: The environment that the API is invoked under.
-// [in] str: Character buffer representing an ISO-8859-1-encoded string.
+// [in] str: The string, whose runes must be in the range U+0000-U+00FF.
+// Returns napi_invalid_arg if str contains runes that cannot be encoded in
+// ISO-8859-1: EncodeLatin1 reports which one.
 // The JavaScript String type is described in Section 6.1.4 of the ECMAScript
 // Language Specification.
 // N-API version: 1
 func CreateStringLatin1(env Env, str string) (Value, Status) {
 	var res C.napi_value
-	cstr := C.CString(str)
-	defer C.free(unsafe.Pointer(cstr))
-	var status = C.napi_create_string_latin1(env, cstr, C.NAPI_AUTO_LENGTH, &res)
+	var buf = getBytes(0)
+	defer putBytes(buf)
+	var err error
+	if *buf, err = appendLatin1(*buf, str); err != nil {
+		return nil, Status(Statuses.InvalidArg)
+	}
+	var data = unsafe.Pointer(&emptyString[0])
+	if len(*buf) > 0 {
+		data = unsafe.Pointer(&(*buf)[0])
+	}
+	var status = C.napi_create_string_latin1(env, (*C.char)(data), C.size_t(len(*buf)), &res)
 	return Value(res), Status(status)
 }
 
-// CreateStringUtf16 function creates a JavaScript String object from a
-// UTF16-LE-encoded C string. The native string is copied.
+// CreateStringUtf16 function creates a JavaScript String object from a Go
+// string, encoded in UTF16-LE before passing it to N-API. The string is
+// copied.
 // [in] env: The environment that the API is invoked under.
-// [in] str: Character buffer representing a UTF16-LE-encoded string.
+// [in] str: The string. Runes out of the Basic Multilingual Plane are encoded
+// as surrogate pairs.
 // The JavaScript String type is described in Section 6.1.4 of the ECMAScript
 // Language Specification.
 // N-API version: 1
 func CreateStringUtf16(env Env, str string) (Value, Status) {
 	var res C.napi_value
-	cstr := (*C.ushort)(cstring(str))
-	defer C.free(unsafe.Pointer(cstr))
-	var status = C.napi_create_string_utf16(env, cstr, C.NAPI_AUTO_LENGTH, &res)
+	var buf = getUTF16(0)
+	defer putUTF16(buf)
+	*buf = appendUTF16(*buf, str)
+	var data = unsafe.Pointer(&emptyString[0])
+	if len(*buf) > 0 {
+		data = unsafe.Pointer(&(*buf)[0])
+	}
+	var status = C.napi_create_string_utf16(env, (*C.char16_t)(data), C.size_t(len(*buf)), &res)
 	return Value(res), Status(status)
 }
 
-// CreateStringUtf8 function creates a JavaScript String object from a
-// UTF8-encoded C string. The native string is copied.
+// CreateStringUtf8 function creates a JavaScript String object from a Go
+// string. The string is copied, including any NUL character.
 // [in] env: The environment that the API is invoked under.
-// [in] str: Character buffer representing a UTF8-encoded string.
+// [in] str: The UTF8-encoded string.
 // The JavaScript String type is described in Section 6.1.4 of the ECMAScript
 // Language Specification.
 // N-API version: 1
 func CreateStringUtf8(env Env, str string) (Value, Status) {
 	var res C.napi_value
-	cstr := C.CString(str)
-	defer C.free(unsafe.Pointer(cstr))
-	var status = C.napi_create_string_utf8(env, cstr, C.NAPI_AUTO_LENGTH, &res)
+	var status = C.napi_create_string_utf8(env, (*C.char)(stringData(str)), C.size_t(len(str)), &res)
 	return Value(res), Status(status)
 }
 
@@ -1225,17 +1331,75 @@ func GetArrayLength(env Env, value Value) (uint32, Status) {
 	return uint32(res), Status(status)
 }
 
-// GetArrayBufferInfo function the underlying data buffer of a node::Buffer
-// and it's length.
+// GetArrayBufferInfo function returns the underlying data buffer of an
+// ArrayBuffer and its length.
 // [in] env: The environment that the API is invoked under.
-//  N-API version: 1
+// [in] arraybuffer: napi_value representing the ArrayBuffer being queried.
+// [out] data: The underlying data buffer of the ArrayBuffer. If byte_length is
+// 0, this may be NULL or any other pointer value.
+// [out] byte_length: Length in bytes of the underlying data buffer.
+// Warning: Use caution while using this API since the underlying data buffer is
+// managed by the VM.
+// N-API version: 1
 func GetArrayBufferInfo(env Env, value Value) (unsafe.Pointer, uint, Status) {
+	var data unsafe.Pointer
+	var length C.size_t
+	var status = C.napi_get_arraybuffer_info(env, value, &data, &length)
+	return data, uint(length), Status(status)
+}
+
+// GetBufferInfo function returns the underlying data buffer of a node::Buffer
+// and its length.
+// [in] env: The environment that the API is invoked under.
+// [in] value: napi_value representing the node::Buffer being queried.
+// [out] data: The underlying data buffer of the node::Buffer. If length is 0,
+// this may be NULL or any other pointer value.
+// [out] length: Length in bytes of the underlying data buffer.
+// Warning: Use caution while using this API since the underlying data buffer
+// may not be managed by the VM.
+// N-API version: 1
+func GetBufferInfo(env Env, value Value) (unsafe.Pointer, uint, Status) {
 	var data unsafe.Pointer
 	var length C.size_t
 	var status = C.napi_get_buffer_info(env, value, &data, &length)
 	return data, uint(length), Status(status)
 }
 
+// DetachArrayBuffer function detaches an ArrayBuffer, as if it was
+// transferred: its byte length and the one of the typed arrays and DataViews
+// over it become 0.
+// [in] env: The environment that the API is invoked under.
+// [in] arraybuffer: The JavaScript ArrayBuffer to be detached.
+// Returns napi_ok if the API succeeded. If a non-detachable ArrayBuffer is
+// passed in it returns napi_detachable_arraybuffer_expected.
+// Generally, an ArrayBuffer is non-detachable if it has been detached before.
+// The engine may impose additional conditions on whether an ArrayBuffer is
+// detachable. For example, V8 requires that the ArrayBuffer be external, that
+// is, created with CreateExternalArrayBuffer.
+// This API represents the invocation of the ArrayBuffer detach operation as
+// defined in Section 24.1.1.3 of the ECMAScript Language Specification.
+// N-API version: 7
+func DetachArrayBuffer(env Env, value Value) Status {
+	var status = C.napi_detach_arraybuffer(env, value)
+	return Status(status)
+}
+
+// IsDetachedArrayBuffer function checks if the ArrayBuffer passed in has been
+// detached.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The JavaScript ArrayBuffer to be checked.
+// [out] result: Whether the arraybuffer is detached.
+// The ArrayBuffer is considered detached if its internal data is null.
+// This API represents the invocation of the ArrayBuffer IsDetachedBuffer
+// operation as defined in Section 24.1.1.2 of the ECMAScript Language
+// Specification.
+// N-API version: 7
+func IsDetachedArrayBuffer(env Env, value Value) (bool, Status) {
+	var res C.bool
+	var status = C.napi_is_detached_arraybuffer(env, value, &res)
+	return bool(res), Status(status)
+}
+
 // GetPrototype function returns a N-API value representing the prototype of
 // the given object.
 // [in] env: The environment that the API is invoked under.
@@ -1295,6 +1459,23 @@ func GetDataviewInfo(env Env, value Value) (Value, uint, uint, Status) {
 	return Value(arraybuffer), uint(length), uint(offset), Status(status)
 }
 
+// GetDateValue function returns the C double primitive of time value for the
+// given JavaScript Date.
+// [in] env: The environment that the API is invoked under.
+// [in] value: napi_value representing a JavaScript Date.
+// [out] result: Time value as a double represented as milliseconds since
+// midnight at the beginning of 01 January, 1970 UTC.
+// This API does not observe leap seconds; they are ignored, as ECMAScript
+// aligns with POSIX time specification.
+// Returns napi_ok if the API succeeded. If a non-date napi_value is passed in
+// it returns napi_date_expected.
+// N-API version: 5
+func GetDateValue(env Env, value Value) (float64, Status) {
+	var res C.double
+	var status = C.napi_get_date_value(env, value, &res)
+	return float64(res), Status(status)
+}
+
 // GetValueBool function returns the C boolean primitive equivalent of the
 // given JavaScript Boolean.
 // [in] env: The environment that the API is invoked under.
@@ -1363,13 +1544,21 @@ func GetValueBigintUInt64(env Env, value Value) (uint64, bool, Status) {
 // Upon return, it will be set to the actual number of words that would be
 // needed to store this BigInt.
 // [out] words: Pointer to a pre-allocated 64-bit word array.
+// The words are returned in a slice allocated after querying their number, so
+// that BigInts of any size are supported.
+// Returns napi_ok if the API succeeded. If a non-BigInt is passed in it returns
+// napi_bigint_expected.
 // N-API version: -
-func GetValueBigintWords(env Env, value Value) (unsafe.Pointer, uint, int, Status) {
+func GetValueBigintWords(env Env, value Value) ([]uint64, int, Status) {
 	var count C.size_t
 	var sign C.int
-	var words unsafe.Pointer
-	var status = C.napi_get_value_bigint_words(env, value, &sign, &count, (*C.uint64_t)(words))
-	return words, uint(count), int(sign), Status(status)
+	var status = C.napi_get_value_bigint_words(env, value, nil, &count, nil)
+	if status != C.napi_ok || count == 0 {
+		return nil, 0, Status(status)
+	}
+	var words = make([]uint64, int(count))
+	status = C.napi_get_value_bigint_words(env, value, &sign, &count, (*C.uint64_t)(unsafe.Pointer(&words[0])))
+	return words[:int(count)], int(sign), Status(status)
 }
 
 // GetValueExternal function returns external data pointer that was
@@ -1416,65 +1605,82 @@ func GetValueInt64(env Env, value Value) (int64, Status) {
 	return int64(res), Status(status)
 }
 
-// GetValueStringLatin1 function returns the ISO-8859-1-encoded string
-// corresponding the value passed in.
+// GetValueStringLatin1 function returns the string corresponding to the value
+// passed in, read as ISO-8859-1 and converted to UTF-8. Characters that cannot
+// be represented in ISO-8859-1 are truncated to their lowest byte by N-API.
 // [in] env: The environment that the API is invoked under.
 // [in] value: napi_value representing JavaScript string.
-// [in] buf: Buffer to write the ISO-8859-1-encoded string into. If NULL is
-// passed in, the length of the string (in bytes) is returned.
-// [in] bufsize: Size of the destination buffer. When this value is insufficient,
-// the returned string will be truncated.
-// [out] result: Number of bytes copied into the buffer, excluding the null
-// terminator.
+// The length of the string is queried first, so the whole string is returned.
 // Returns napi_ok if the API succeeded. If a non-String napi_value is passed in
 // it returns napi_string_expected.
 // N-API version: 1
-func GetValueStringLatin1(env Env, value Value, len uint) (string, Status) {
-	var buf (*C.char)
-	var res C.size_t
-	var status = C.napi_get_value_string_latin1(env, value, buf, C.size_t(len), &res)
-	return string(C.GoStringN(buf, C.int(res))), Status(status)
+func GetValueStringLatin1(env Env, value Value) (string, Status) {
+	var length C.size_t
+	var status = C.napi_get_value_string_latin1(env, value, nil, 0, &length)
+	if status != C.napi_ok || length == 0 {
+		return "", Status(status)
+	}
+	var text = getBytes(int(length) + 1)
+	defer putBytes(text)
+	status = C.napi_get_value_string_latin1(env, value, (*C.char)(unsafe.Pointer(&(*text)[0])), length+1, &length)
+	if status != C.napi_ok {
+		return "", Status(status)
+	}
+	var buf = getBytes(0)
+	defer putBytes(buf)
+	*buf = appendLatin1Runes(*buf, (*text)[:length])
+	return string(*buf), Status(status)
 }
 
-// GetValueStringUtf8 function returns the UTF16-encoded string corresponding
-// the value passed in.
+// GetValueStringUtf8 function returns the string corresponding to the value
+// passed in, encoded in UTF-8.
 // [in] env: The environment that the API is invoked under.
 // [in] value: napi_value representing JavaScript string.
-// [in] buf: Buffer to write the UTF8-encoded string into. If NULL is passed in,
-// the length of the string (in bytes) is returned.
-// [in] bufsize: Size of the destination buffer. When this value is insufficient,
-// the returned string will be truncated.
-// [out] result: Number of bytes copied into the buffer, excluding the null
-// terminator.
+// The length of the string is queried first, so the whole string is returned,
+// including any NUL character.
 // Returns napi_ok if the API succeeded. If a non-String napi_value is passed in
 // it returns napi_string_expected.
 // N-API version: 1
-func GetValueStringUtf8(env Env, value Value, len uint) (string, Status) {
-	var buf (*C.char)
-	var res C.size_t
-	var status = C.napi_get_value_string_utf8(env, value, buf, C.size_t(len), &res)
-	return string(C.GoStringN(buf, C.int(res))), Status(status)
+func GetValueStringUtf8(env Env, value Value) (string, Status) {
+	var length C.size_t
+	var status = C.napi_get_value_string_utf8(env, value, nil, 0, &length)
+	if status != C.napi_ok || length == 0 {
+		return "", Status(status)
+	}
+	var buf = getBytes(int(length) + 1)
+	defer putBytes(buf)
+	status = C.napi_get_value_string_utf8(env, value, (*C.char)(unsafe.Pointer(&(*buf)[0])), length+1, &length)
+	if status != C.napi_ok {
+		return "", Status(status)
+	}
+	return string((*buf)[:length]), Status(status)
 }
 
-// GetValueStringUtf16 function returns the UTF16-encoded string
-// corresponding the value passed in.
+// GetValueStringUtf16 function returns the string corresponding to the value
+// passed in, read as UTF16-LE and converted to UTF-8.
 // [in] env: The environment that the API is invoked under.
 // [in] value: napi_value representing JavaScript string.
-// [in] buf: Buffer to write the UTF16-LE-encoded string into. If NULL is passed
-// in, the length of the string (in 2-byte code units) is returned.
-// [in] bufsize: Size of the destination buffer. When this value is insufficient,
-// the returned string will be truncated.
-// [out] result: Number of 2-byte code units copied into the buffer, excluding
-// the null terminator.
+// The length of the string is queried first, so the whole string is returned.
+// Surrogate pairs are decoded, while unpaired surrogates become U+FFFD.
 // Returns napi_ok if the API succeeded. If a non-String napi_value is passed in
 // it returns napi_string_expected.
 // N-API version: 1
-func GetValueStringUtf16(env Env, value Value, len uint) (string, Status) {
-	var buf (*C.ushort)
-	var res C.size_t
-	var status = C.napi_get_value_string_utf16(env, value, buf, C.size_t(len), &res)
-	var str = bytes.NewBuffer(C.GoBytes(unsafe.Pointer(buf), C.int(res))).String()
-	return str, Status(status)
+func GetValueStringUtf16(env Env, value Value) (string, Status) {
+	var length C.size_t
+	var status = C.napi_get_value_string_utf16(env, value, nil, 0, &length)
+	if status != C.napi_ok || length == 0 {
+		return "", Status(status)
+	}
+	var text = getUTF16(int(length) + 1)
+	defer putUTF16(text)
+	status = C.napi_get_value_string_utf16(env, value, (*C.char16_t)(unsafe.Pointer(&(*text)[0])), length+1, &length)
+	if status != C.napi_ok {
+		return "", Status(status)
+	}
+	var buf = getBytes(0)
+	defer putBytes(buf)
+	*buf = appendUTF16Runes(*buf, (*text)[:length])
+	return string(*buf), Status(status)
 }
 
 // GetValueUint32 function returns the C primitive equivalent of the
@@ -1659,6 +1865,18 @@ func IsDataview(env Env, value Value) (bool, Status) {
 	return bool(res), Status(status)
 }
 
+// IsDate function checks if the Object passed in is a date.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The JavaScript value to check.
+// [out] result: Whether the given napi_value represents a JavaScript Date
+// object.
+// N-API version: 5
+func IsDate(env Env, value Value) (bool, Status) {
+	var res C.bool
+	var status = C.napi_is_date(env, value, &res)
+	return bool(res), Status(status)
+}
+
 // StrictEquals function is simnilar to invoke the Strict Equality algorithm
 // as defined in Section 7.2.14 of the ECMAScript Language Specification.
 // [in] env: The environment that the API is invoked under.
@@ -1683,6 +1901,48 @@ func GetPropertyNames(env Env, object Value) (Value, Status) {
 	return Value(res), Status(status)
 }
 
+// GetAllPropertyNames function returns an array with the names of the
+// available properties of object.
+// [in] env: The environment that the N-API call is invoked under.
+// [in] object: The object from which to retrieve the properties.
+// [in] key_mode: Whether to retrieve prototype properties as well.
+// [in] key_filter: Which properties to retrieve (enumerable/readable/writable).
+// [in] key_conversion: Whether to convert numbered property keys to strings.
+// [out] result: A napi_value representing an array of JavaScript values that
+// represent the property names of the object.
+// N-API version: 6
+func GetAllPropertyNames(env Env, object Value, mode KeyCollectionMode, filter KeyFilter, conversion KeyConversion) (Value, Status) {
+	var res C.napi_value
+	var status = C.napi_get_all_property_names(env, object, mode, filter, conversion, &res)
+	return Value(res), Status(status)
+}
+
+// ObjectFreeze function freezes a given object. This prevents new properties
+// from being added to it, existing properties from being removed, prevents
+// changing the enumerability, configurability, or writability of existing
+// properties, and prevents the values of existing properties from being
+// changed. It also prevents the object's prototype from being changed. This is
+// described in Section 19.1.2.6 of the ECMA-262 specification.
+// [in] env: The environment that the N-API call is invoked under.
+// [in] object: The object to freeze.
+// N-API version: 8
+func ObjectFreeze(env Env, object Value) Status {
+	var status = C.napi_object_freeze(env, object)
+	return Status(status)
+}
+
+// ObjectSeal function seals a given object. This prevents new properties from
+// being added to it, as well as marking all existing properties as
+// non-configurable. This is described in Section 19.1.2.20 of the ECMA-262
+// specification.
+// [in] env: The environment that the N-API call is invoked under.
+// [in] object: The object to seal.
+// N-API version: 8
+func ObjectSeal(env Env, object Value) Status {
+	var status = C.napi_object_seal(env, object)
+	return Status(status)
+}
+
 // SetProperty function set a property on the Object passed in.
 // [in] env: The environment that the N-API call is invoked under.
 // [in] object: The object on which to set the property.
@@ -1828,13 +2088,6 @@ func DeleteElement(env Env, object Value, index uint) (bool, Status) {
 	return bool(res), Status(status)
 }
 
-func unixNano(env Env, info CallbackInfo) Value {
-	fmt.Println("unixNano ...")
-	now := time.Now()
-	value, _ := CreateInt64(env, now.UnixNano())
-	return value
-}
-
 // DefineProperties function allows the efficient definition of multiple
 // properties on a given object. The properties are defined using property
 // descriptors.
@@ -1845,33 +2098,22 @@ func unixNano(env Env, info CallbackInfo) Value {
 // [in] object: The object from which to retrieve the properties.
 // [in] property_count: The number of elements in the properties array.
 // [in] properties: The array of property descriptors.
+// Returns napi_invalid_arg if value is nil, without registering the callbacks
+// of the properties.
 // N-API version: 1
 func DefineProperties(env Env, value Value, properties []Property) Status {
-	raw := make([]PropertyDescriptor, len(properties))
-	for i := range properties {
-		properties[i].getRaw()
-		//raw[i] = prop
+	if value == nil {
+		return Status(C.napi_invalid_arg)
 	}
-	name := C.CString("unixNano")
-	defer C.free(unsafe.Pointer(name))
-	hello, _ := CreateStringUtf8(env, "hello")
-	caller := &Caller{
-		Cb: unixNano,
-	}
-	desc := PropertyDescriptor{
-		utf8name:   name,
-		name:       nil,
-		method:     C.Callback(unsafe.Pointer(caller)),
-		getter:     nil,
-		setter:     nil,
-		value:      hello,
-		attributes: C.napi_default,
-		data:       nil,
+	if len(properties) == 0 {
+		return Status(C.napi_define_properties(env, value, 0, nil))
 	}
-	raw[0] = desc
-	var props = (unsafe.Pointer(&raw[0]))
-	var status = C.napi_define_properties(env, value, C.size_t(len(properties)), (*C.napi_property_descriptor)(props))
-	return Status(status)
+	raw, handles, free := getRawProperties(properties)
+	defer free()
+	var props = (*C.napi_property_descriptor)(unsafe.Pointer(&raw[0]))
+	var status = C.napi_define_properties(env, value, C.size_t(len(raw)), props)
+	// The callbacks live as long as the object they are defined on.
+	return attachHandles(env, value, handles, status)
 }
 
 // Working with JavaScript Functions
@@ -1903,11 +2145,20 @@ func DefineProperties(env Env, value Value, properties []Property) Status {
 // N-API version: 1
 func CallFunction(env Env, receiver Value, function Value, arguments []Value) (Value, Status) {
 	var res C.napi_value
-	var args = unsafe.Pointer(&arguments[0])
-	var status = C.napi_call_function(env, receiver, function, C.size_t(len(arguments)), (*C.napi_value)(args), &res)
+	var args = valuesPointer(arguments)
+	var status = C.napi_call_function(env, receiver, function, C.size_t(len(arguments)), args, &res)
 	return Value(res), Status(status)
 }
 
+// valuesPointer returns the pointer to the first of values, that is nil if
+// there are no values.
+func valuesPointer(values []Value) *C.napi_value {
+	if len(values) == 0 {
+		return nil
+	}
+	return (*C.napi_value)(unsafe.Pointer(&values[0]))
+}
+
 // CreateFunction function allows an add-on author to create a function
 // object in native code. This is the primary mechanism to allow calling into
 // the add-on's native code from JavaScript.
@@ -1926,9 +2177,12 @@ func CreateFunction(env Env, name string, cb CCallback) (Value, Status) {
 		Cb: cb,
 	}
 	var res C.napi_value
-	var cname = C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	var status = C.napi_create_function(env, cname, C.NAPI_AUTO_LENGTH, (Callback)(C.Callback(unsafe.Pointer(caller))), nil, &res)
+	var h = callbacks.add(caller)
+	var status = C.CreateFunction(env, (*C.char)(stringData(name)), C.size_t(len(name)), C.uintptr_t(h), &res)
+	if status != C.napi_ok || res == nil {
+		callbacks.remove(h)
+		return nil, Status(status)
+	}
 	return Value(res), Status(status)
 }
 
@@ -1941,27 +2195,27 @@ func CreateFunction(env Env, name string, cb CCallback) (Value, Status) {
 // actual count of arguments.
 // [out] argv: Buffer to which the napi_value representing the arguments are copied. If there are more arguments than the provided count, only the requested number of arguments are copied. If there are fewer arguments provided than claimed, the rest of argv is filled with napi_value values that represent undefined.
 // [out] this: Receives the JavaScript this argument for the call.
-// [out] data: Receives the data pointer for the callback.
+// [out] data: Receives the data pointer for the callback, that is the Data of
+// the Property for methods and accessors.
+// CallContext reads the same details lazily, without allocating the arguments.
 // N-API version: 1
 func GetCbInfo(env Env, cbinfo CallbackInfo) ([]Value, Value, unsafe.Pointer, Status) {
-	var staticArgc C.size_t = 5
-	var argc = staticArgc
-	staticArgv := make([]Value, 5)
-	var dynamicArgv []Value
-	var cStaticArgv = (unsafe.Pointer(&staticArgv[0]))
+	var inline [inlineArgs]Value
+	var argc C.size_t = inlineArgs
 	var thisArg C.napi_value
 	var data unsafe.Pointer
-	var status = C.napi_get_cb_info(env, cbinfo, &argc, (*C.napi_value)(cStaticArgv), &thisArg, &data)
-	if argc > staticArgc {
-		dynamicArgv = make([]Value, int(argc))
-		var cDynamicArgv = (unsafe.Pointer(&dynamicArgv[0]))
-		var status = C.napi_get_cb_info(env, cbinfo, &argc, (*C.napi_value)(cDynamicArgv), nil, nil)
-		return dynamicArgv, Value(thisArg), data, Status(status)
+	var status = C.napi_get_cb_info(env, cbinfo, &argc, valuesPointer(inline[:]), &thisArg, &data)
+	// The data pointer of the callbacks is the handle of their registry entry.
+	data = callbackData(handle(uintptr(data)))
+	if status != C.napi_ok || argc == 0 {
+		return nil, Value(thisArg), data, Status(status)
 	}
 	arguments := make([]Value, int(argc))
-	for i := 0; i < int(argc); i++ {
-		arguments[i] = staticArgv[i]
+	if argc > inlineArgs {
+		status = C.napi_get_cb_info(env, cbinfo, &argc, valuesPointer(arguments), nil, nil)
+		return arguments, Value(thisArg), data, Status(status)
 	}
+	copy(arguments, inline[:])
 	return arguments, Value(thisArg), data, Status(status)
 }
 
@@ -1989,9 +2243,8 @@ func GetNewTarget(env Env, cbinfo CallbackInfo) (Value, Status) {
 // N-API version: 1
 func NewInstance(env Env, ctor Value, arguments []Value) (Value, Status) {
 	var res C.napi_value
-	var args = unsafe.Pointer(&arguments[0])
-	// defer C.free(args)
-	var status = C.napi_new_instance(env, ctor, C.size_t(len(arguments)), (*C.napi_value)(args), &res)
+	var args = valuesPointer(arguments)
+	var status = C.napi_new_instance(env, ctor, C.size_t(len(arguments)), args, &res)
 	return Value(res), Status(status)
 }
 
@@ -2045,12 +2298,26 @@ func NewInstance(env Env, ctor Value, arguments []Value) (Value, Status) {
 // parameter) and freed whenever the class is garbage-collected by passing both
 // the JavaScript function and the data to NapiAddFinalizer.
 // N-API version: 1
-func DefineClass(env Env, name string, ctor Callback, properties []PropertyDescriptor) (Value, Status) {
+func DefineClass(env Env, name string, ctor *Caller, properties []Property) (Value, Status) {
 	var res C.napi_value
 	cname := C.CString(name)
 	defer C.free(unsafe.Pointer(cname))
-	props := unsafe.Pointer(&properties[0])
-	var status = C.napi_define_class(env, cname, C.NAPI_AUTO_LENGTH, ctor, nil, C.size_t(len(properties)), (*C.napi_property_descriptor)(props), &res)
+	raw, handles, free := getRawProperties(properties)
+	defer free()
+	var props *C.napi_property_descriptor
+	if len(raw) > 0 {
+		props = (*C.napi_property_descriptor)(unsafe.Pointer(&raw[0]))
+	}
+	var h = callbacks.add(ctor)
+	var status = C.DefineClass(env, cname, C.NAPI_AUTO_LENGTH, C.uintptr_t(h), C.size_t(len(raw)), props, &res)
+	if status != C.napi_ok || res == nil {
+		callbacks.remove(h)
+	}
+	// The callbacks of the static and the instance properties live as long as
+	// the class.
+	if status := attachHandles(env, res, handles, status); status != Status(Statuses.OK) {
+		return nil, status
+	}
 	return Value(res), Status(status)
 }
 
@@ -2145,8 +2412,15 @@ func RemoveWrap(env Env, value Value) (unsafe.Pointer, Status) {
 // N-API version: 1
 func AddFinalizer(env Env, obj Value, native unsafe.Pointer, finalizer *FinalizeCaller, hint unsafe.Pointer) (Ref, Status) {
 	var res C.napi_ref
-	var fn = C.FinalizeCallback(unsafe.Pointer(finalizer))
-	var status = C.napi_add_finalizer(env, obj, native, fn, hint, &res)
+	var entry = &finalizeEntry{
+		caller: finalizer,
+		hint:   hint,
+	}
+	var h = callbacks.add(entry)
+	var status = C.AddFinalizer(env, obj, native, C.uintptr_t(h), &res)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+	}
 	return Ref(res), Status(status)
 }
 
@@ -2202,10 +2476,18 @@ func AddFinalizer(env Env, obj Value, native unsafe.Pointer, finalizer *Finalize
 // N-API version: 1
 func CreateAsyncWork(env Env, resource Value, name Value, execute *AsyncExecuteCaller, complete *AsyncCompleteCaller, data unsafe.Pointer) (AsyncWork, Status) {
 	var res C.napi_async_work
-	cexecute := C.AsyncExecuteCallback(unsafe.Pointer(execute))
-	ccomplete := C.AsyncCompleteCallback(unsafe.Pointer(complete))
-	cdata := unsafe.Pointer(data)
-	var status = C.napi_create_async_work(env, resource, name, cexecute, ccomplete, cdata, &res)
+	var entry = &asyncWorkEntry{
+		execute:  execute,
+		complete: complete,
+		data:     data,
+	}
+	var h = callbacks.add(entry)
+	var status = C.CreateAsyncWork(env, resource, name, C.uintptr_t(h), &res)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+		return AsyncWork(res), Status(status)
+	}
+	asyncWorks.Store(AsyncWork(res), h)
 	return AsyncWork(res), Status(status)
 }
 
@@ -2216,6 +2498,10 @@ func CreateAsyncWork(env Env, resource Value, name Value, execute *AsyncExecuteC
 // N-API version: 1
 func DeleteAsyncWork(env Env, work AsyncWork) Status {
 	var status = C.napi_delete_async_work(env, work)
+	if h, ok := asyncWorks.Load(work); ok && status == C.napi_ok {
+		asyncWorks.Delete(work)
+		callbacks.remove(h.(handle))
+	}
 	return Status(status)
 }
 
@@ -2291,7 +2577,7 @@ func AsyncDestroy(env Env, ctx AsyncContext) Status {
 // N-API version: 1
 func MakeCallback(env Env, ctx AsyncContext, recv Value, fn Value, args []Value) (Value, Status) {
 	var res C.napi_value
-	var argv = (*C.napi_value)(unsafe.Pointer(&args[0]))
+	var argv = valuesPointer(args)
 	var argc = C.size_t(len(args))
 	var status = C.napi_make_callback(env, ctx, recv, fn, argc, argv, &res)
 	return Value(res), Status(status)
@@ -2480,9 +2766,16 @@ func GetUvEventLoop(env Env) (UVLoop, Status) {
 // N-API version: 4
 func CreateThreadsafeFunction(env Env, fn Value, resource Value, name Value, maxQueueSize uint, initialThreadCount uint, data unsafe.Pointer, finalizer *FinalizeCaller, ctx unsafe.Pointer, tsfn *ThreadsafeFunctionsCaller) (ThreadsafeFunction, Status) {
 	var res C.napi_threadsafe_function
-	ctsfn := C.ThreadsafeFunctionCallback(unsafe.Pointer(tsfn))
-	cfinalize := C.FinalizeCallback(unsafe.Pointer(finalizer))
-	var status = C.napi_create_threadsafe_function(env, fn, resource, name, C.size_t(maxQueueSize), C.size_t(initialThreadCount), data, cfinalize, ctx, ctsfn, &res)
+	var entry = &threadsafeFunctionEntry{
+		call:     tsfn,
+		finalize: finalizer,
+		context:  ctx,
+	}
+	var h = callbacks.add(entry)
+	var status = C.CreateThreadsafeFunction(env, fn, resource, name, C.size_t(maxQueueSize), C.size_t(initialThreadCount), data, C.uintptr_t(h), C.bool(tsfn != nil), &res)
+	if status != C.napi_ok {
+		callbacks.remove(h)
+	}
 	return ThreadsafeFunction(res), Status(status)
 }
 
@@ -2493,9 +2786,12 @@ func CreateThreadsafeFunction(env Env, fn Value, resource Value, name Value, max
 // function.
 // N-API version: 4
 func GetThreadsafeFunctionContext(fn ThreadsafeFunction) (unsafe.Pointer, Status) {
-	var res unsafe.Pointer
-	var status = C.napi_get_threadsafe_function_context(fn, &res)
-	return res, Status(status)
+	var res C.uintptr_t
+	var status = C.napi_get_threadsafe_function_context(fn, (*unsafe.Pointer)(unsafe.Pointer(&res)))
+	if entry, ok := callbacks.get(handle(res)); ok {
+		return entry.(*threadsafeFunctionEntry).context, Status(status)
+	}
+	return nil, Status(status)
 }
 
 // CallThreadsafeFunction function ...
@@ -2573,12 +2869,67 @@ type Caller struct {
 	Cb CCallback
 }
 
+//export ReleaseHandle
+func ReleaseHandle(wrap C.uintptr_t) {
+	callbacks.remove(handle(wrap))
+}
+
+// throwReleased throws an Error for a call of a callback whose handle has been
+// released, as its function, object or class has been garbage-collected while
+// the function was kept, like a method read from an object.
+func throwReleased(env Env) C.napi_value {
+	ThrowError(env, "napi: callback called after its owner was garbage-collected", "")
+	return nil
+}
+
 //export CallCallback
-func CallCallback(wrap unsafe.Pointer, env C.napi_env, info C.napi_callback_info) C.napi_value {
-	caller := (*Caller)(wrap)
+func CallCallback(wrap C.uintptr_t, env C.napi_env, info C.napi_callback_info) (res C.napi_value) {
+	defer recoverThrow(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return throwReleased(Env(env))
+	}
+	var caller *Caller
+	switch entry := entry.(type) {
+	case *Caller:
+		caller = entry
+	case *propertyEntry:
+		caller = entry.method
+	}
+	if caller == nil {
+		return nil
+	}
 	return (C.napi_value)(caller.Cb(Env(env), CallbackInfo(info)))
 }
 
+//export CallGetterCallback
+func CallGetterCallback(wrap C.uintptr_t, env C.napi_env, info C.napi_callback_info) (res C.napi_value) {
+	defer recoverThrow(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return throwReleased(Env(env))
+	}
+	prop := entry.(*propertyEntry)
+	if prop.getter == nil {
+		return nil
+	}
+	return (C.napi_value)(prop.getter.Cb(Env(env), CallbackInfo(info)))
+}
+
+//export CallSetterCallback
+func CallSetterCallback(wrap C.uintptr_t, env C.napi_env, info C.napi_callback_info) (res C.napi_value) {
+	defer recoverThrow(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return throwReleased(Env(env))
+	}
+	prop := entry.(*propertyEntry)
+	if prop.setter == nil {
+		return nil
+	}
+	return (C.napi_value)(prop.setter.Cb(Env(env), CallbackInfo(info)))
+}
+
 // CAsyncExecuteCallback  ...
 type CAsyncExecuteCallback func(Env, unsafe.Pointer)
 
@@ -2587,12 +2938,6 @@ type AsyncExecuteCaller struct {
 	Cb CAsyncExecuteCallback
 }
 
-//export CallAsyncExecuteCallback
-func CallAsyncExecuteCallback(wrap unsafe.Pointer, env C.napi_env, data unsafe.Pointer) {
-	caller := (*AsyncExecuteCaller)(wrap)
-	caller.Cb(env, data)
-}
-
 // CAsyncExecuteCallback  ...
 type CAsyncCompleteCallback func(Env, Status, unsafe.Pointer)
 
@@ -2601,10 +2946,56 @@ type AsyncCompleteCaller struct {
 	Cb CAsyncCompleteCallback
 }
 
+// asyncWorkEntry contains the callbacks and the data of an asynchronous work.
+type asyncWorkEntry struct {
+	execute  *AsyncExecuteCaller
+	complete *AsyncCompleteCaller
+	data     unsafe.Pointer
+	// The panic recovered from the execute callback, reported to JavaScript
+	// from the main thread when the work completes.
+	recovered interface{}
+	stack     []byte
+}
+
+// asyncWorks maps every asynchronous work to the handle of its callbacks, so
+// that they can be released when the work is deleted.
+var asyncWorks sync.Map
+
+//export CallAsyncExecuteCallback
+func CallAsyncExecuteCallback(wrap C.uintptr_t, env C.napi_env) {
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	work := entry.(*asyncWorkEntry)
+	defer func() {
+		// The execute callback runs outside the main thread, where no N-API
+		// call can be made, so the panic is reported on completion.
+		if recovered := recover(); recovered != nil {
+			work.recovered = recovered
+			work.stack = debug.Stack()
+		}
+	}()
+	if work.execute != nil {
+		work.execute.Cb(env, work.data)
+	}
+}
+
 //export CallAsyncCompleteCallback
-func CallAsyncCompleteCallback(wrap unsafe.Pointer, env C.napi_env, status C.napi_status, data unsafe.Pointer) {
-	caller := (*AsyncCompleteCaller)(wrap)
-	caller.Cb(env, status, data)
+func CallAsyncCompleteCallback(wrap C.uintptr_t, env C.napi_env, status C.napi_status) {
+	defer recoverFatal(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	work := entry.(*asyncWorkEntry)
+	if work.recovered != nil {
+		fatalPanic(env, work.recovered, work.stack)
+		status = C.napi_generic_failure
+	}
+	if work.complete != nil {
+		work.complete.Cb(env, Status(status), work.data)
+	}
 }
 
 // CFinalizeCallback  ...
@@ -2615,10 +3006,24 @@ type FinalizeCaller struct {
 	Cb CFinalizeCallback
 }
 
+// finalizeEntry contains a finalizer and the hint to pass to it.
+type finalizeEntry struct {
+	caller *FinalizeCaller
+	hint   unsafe.Pointer
+}
+
 //export CallFinalizeCallback
-func CallFinalizeCallback(wrap unsafe.Pointer, env C.napi_env, data unsafe.Pointer, hint unsafe.Pointer) {
-	caller := (*FinalizeCaller)(wrap)
-	caller.Cb(env, data, hint)
+func CallFinalizeCallback(wrap C.uintptr_t, env C.napi_env, data unsafe.Pointer) {
+	defer recoverFatal(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	defer callbacks.remove(handle(wrap))
+	finalizer := entry.(*finalizeEntry)
+	if finalizer.caller != nil {
+		finalizer.caller.Cb(env, data, finalizer.hint)
+	}
 }
 
 // CThreadsafeFunctionsCallback  ...
@@ -2629,31 +3034,149 @@ type ThreadsafeFunctionsCaller struct {
 	Cb CThreadsafeFunctionsCallback
 }
 
+// threadsafeFunctionEntry contains the callbacks and the context of a
+// thread-safe function.
+type threadsafeFunctionEntry struct {
+	call     *ThreadsafeFunctionsCaller
+	finalize *FinalizeCaller
+	context  unsafe.Pointer
+}
+
 //export CallThreadsafeFunctionCallback
-func CallThreadsafeFunctionCallback(wrap unsafe.Pointer, env C.napi_env, fn C.napi_value, ctx unsafe.Pointer, data unsafe.Pointer) {
-	caller := (*ThreadsafeFunctionsCaller)(wrap)
-	caller.Cb(env, fn, ctx, data)
+func CallThreadsafeFunctionCallback(wrap C.uintptr_t, env C.napi_env, fn C.napi_value, data unsafe.Pointer) {
+	defer recoverFatal(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	tsfn := entry.(*threadsafeFunctionEntry)
+	tsfn.call.Cb(env, fn, tsfn.context, data)
 }
 
-// Property ...
-type Property struct {
-	Name   string
-	Method *Caller
+//export CallThreadsafeFunctionFinalizeCallback
+func CallThreadsafeFunctionFinalizeCallback(wrap C.uintptr_t, env C.napi_env, data unsafe.Pointer) {
+	defer recoverFatal(env)
+	entry, ok := callbacks.get(handle(wrap))
+	if !ok {
+		return
+	}
+	defer callbacks.remove(handle(wrap))
+	tsfn := entry.(*threadsafeFunctionEntry)
+	if tsfn.finalize != nil {
+		tsfn.finalize.Cb(env, data, tsfn.context)
+	}
 }
 
-// GetRaw ...
-func (prop *Property) getRaw() PropertyDescriptor {
-	name := C.CString(prop.Name)
-	defer C.free(unsafe.Pointer(name))
+// Property describes a property defined with DefineProperties or DefineClass.
+// The property is either a method, an accessor with a getter and (or) a
+// setter, or a data property holding Value.
+type Property struct {
+	// Name is the name of the property. It is ignored if Key is set.
+	Name string
+	// Key is optionally the JavaScript string or symbol used as key of the
+	// property.
+	Key Value
+	// Method is the function called when the property is invoked as a method.
+	Method *Caller
+	// Getter is the function called when the property is read.
+	Getter *Caller
+	// Setter is the function called when the property is written.
+	Setter *Caller
+	// Value is the value of a data property.
+	Value Value
+	// Attributes is a combination of the PropertyAttributes flags.
+	Attributes int
+	// Data is returned by GetCbInfo when the method, the getter or the setter
+	// is called.
+	Data unsafe.Pointer
+}
+
+// propertyEntry contains the callbacks and the data of a property. They are
+// registered under a single handle, as a descriptor has a single data pointer.
+type propertyEntry struct {
+	method *Caller
+	getter *Caller
+	setter *Caller
+	data   unsafe.Pointer
+}
+
+// getRaw returns the N-API property descriptor for the property and the handle
+// of its callbacks, if any. The name of the descriptor is allocated in C memory
+// and must be freed by the caller.
+func (prop *Property) getRaw() (PropertyDescriptor, handle) {
 	desc := PropertyDescriptor{
-		utf8name:   name,
-		name:       nil,
-		method:     nil, //(Callback)(C.Callback(unsafe.Pointer(prop.Method))), //nil,
+		utf8name:   nil,
+		name:       prop.Key,
+		method:     nil,
 		getter:     nil,
 		setter:     nil,
-		value:      nil,
-		attributes: C.napi_default,
+		value:      prop.Value,
+		attributes: C.napi_property_attributes(prop.Attributes),
 		data:       nil,
 	}
-	return desc
+	if prop.Key == nil {
+		desc.utf8name = C.CString(prop.Name)
+	}
+	var h handle
+	if prop.Method != nil || prop.Getter != nil || prop.Setter != nil {
+		h = callbacks.add(&propertyEntry{
+			method: prop.Method,
+			getter: prop.Getter,
+			setter: prop.Setter,
+			data:   prop.Data,
+		})
+		C.SetPropertyCallbacks(&desc, C.uintptr_t(h), prop.Method != nil, prop.Getter != nil, prop.Setter != nil)
+	}
+	return desc, h
+}
+
+// getRawProperties returns the N-API property descriptors for the properties
+// and the handles of their callbacks. The returned function frees the names
+// of the descriptors.
+func getRawProperties(properties []Property) ([]PropertyDescriptor, []C.uintptr_t, func()) {
+	raw := make([]PropertyDescriptor, len(properties))
+	handles := make([]C.uintptr_t, 0, len(properties))
+	for i := range properties {
+		desc, h := properties[i].getRaw()
+		raw[i] = desc
+		if h != 0 {
+			handles = append(handles, C.uintptr_t(h))
+		}
+	}
+	free := func() {
+		for i := range raw {
+			C.free(unsafe.Pointer(raw[i].utf8name))
+		}
+	}
+	return raw, handles, free
+}
+
+// attachHandles ties the lifetime of the handles to the object if status is
+// OK, otherwise it removes them. If only some of the handles can be tied to
+// the object, the others are removed.
+func attachHandles(env Env, object Value, handles []C.uintptr_t, status C.napi_status) Status {
+	if len(handles) == 0 {
+		return Status(status)
+	}
+	var attached C.size_t
+	if status == C.napi_ok {
+		status = C.AttachHandles(env, object, &handles[0], C.size_t(len(handles)), &attached)
+	}
+	if status != C.napi_ok {
+		for _, h := range handles[attached:] {
+			callbacks.remove(handle(h))
+		}
+	}
+	return Status(status)
+}
+
+// callbackData returns the data of the property registered under the handle
+// received by a callback, if any.
+func callbackData(h handle) unsafe.Pointer {
+	if entry, ok := callbacks.get(h); ok {
+		if prop, ok := entry.(*propertyEntry); ok {
+			return prop.data
+		}
+	}
+	return nil
 }
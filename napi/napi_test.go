@@ -1,10 +1,679 @@
 package napi
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
 
-func TestHello(t *testing.T) {
-	want := "Hello, world."
-	if got := Hello(); got != want {
-		t.Errorf("Hello() = %q, want %q", got, want)
+func TestRegistryHandles(t *testing.T) {
+	r := &registry{entries: make(map[handle]interface{})}
+	first := &Caller{}
+	second := &Caller{}
+	h1 := r.add(first)
+	h2 := r.add(second)
+	if h1 == 0 || h2 == 0 {
+		t.Fatalf("add() returned the zero handle")
+	}
+	if h1 == h2 {
+		t.Fatalf("add() returned the same handle %d twice", h1)
+	}
+	if got, ok := r.get(h1); !ok || got != first {
+		t.Errorf("get(%d) = %v, %v, want %v, true", h1, got, ok, first)
+	}
+	if got, ok := r.get(h2); !ok || got != second {
+		t.Errorf("get(%d) = %v, %v, want %v, true", h2, got, ok, second)
+	}
+	if _, ok := r.get(h2 + 1); ok {
+		t.Errorf("get(%d) found an entry that was never added", h2+1)
+	}
+}
+
+func TestRegistryStats(t *testing.T) {
+	r := &registry{entries: make(map[handle]interface{})}
+	h1 := r.add(&Caller{})
+	h2 := r.add(&FinalizeCaller{})
+	r.add(&AsyncExecuteCaller{})
+	r.remove(h1)
+	r.remove(h1)
+	r.remove(h2)
+	want := RegistryStats{Live: 1, Registered: 3, Released: 2}
+	if got := r.stats(); got != want {
+		t.Errorf("stats() = %+v, want %+v", got, want)
+	}
+	if _, ok := r.get(h1); ok {
+		t.Errorf("get(%d) found a removed entry", h1)
+	}
+}
+
+func TestCallbackHandlesRelease(t *testing.T) {
+	cb := func(env Env, info CallbackInfo) Value { return nil }
+	live := GetRegistryStats().Live
+	// The stub library creates no function, so its handle is released.
+	if fn, _ := CreateFunction(nil, "f", cb); fn != nil || GetRegistryStats().Live != live {
+		t.Errorf("CreateFunction() kept the handle of a function that was not created")
+	}
+	props := []Property{{Name: "m", Method: &Caller{Cb: cb}}, {Name: "v"}}
+	if status := DefineProperties(nil, nil, props); status != Status(Statuses.InvalidArg) || GetRegistryStats().Live != live {
+		t.Errorf("DefineProperties() on no object = %v, keeping %d handles", status, GetRegistryStats().Live-live)
+	}
+}
+
+func TestPanicMessage(t *testing.T) {
+	tests := []struct {
+		recovered interface{}
+		want      string
+	}{
+		{"boom", "boom"},
+		{errors.New("broken"), "broken"},
+		{42, "42"},
+	}
+	for _, test := range tests {
+		if got := panicMessage(test.recovered); got != test.want {
+			t.Errorf("panicMessage(%#v) = %q, want %q", test.recovered, got, test.want)
+		}
+	}
+}
+
+func TestExportsCollisions(t *testing.T) {
+	fn := func(env Env, exports Value) Value { return nil }
+	r := &exportsRegistry{namespaces: make(map[string]ModuleInit)}
+	r.register("crypto", fn)
+	r.register("fs", fn)
+	if got := r.check([]string{"version"}); len(got) != 0 {
+		t.Errorf("check() = %q, want no collisions", got)
+	}
+	r.register("crypto", fn)
+	if got := r.check([]string{"fs"}); len(got) != 2 {
+		t.Errorf("check() = %q, want 2 collisions", got)
+	}
+	if got, want := strings.Join(r.names(), ","), "crypto,fs"; got != want {
+		t.Errorf("names() = %q, want %q", got, want)
+	}
+	if name, ok := propertyName(nil, &Property{Name: "fs"}); name != "fs" || !ok {
+		t.Errorf("propertyName() = %q, %v", name, ok)
+	}
+}
+
+func TestInstanceDataRelease(t *testing.T) {
+	var finalized []string
+	local := &EnvLocal{
+		Finalize: func(env Env, value interface{}) {
+			finalized = append(finalized, value.(string))
+		},
+	}
+	data := &instanceData{
+		data: "data",
+		finalize: func(env Env, value interface{}) {
+			finalized = append(finalized, value.(string))
+		},
+		locals: map[*EnvLocal]interface{}{
+			local:       "local",
+			&EnvLocal{}: "ignored",
+		},
+	}
+	data.release(nil)
+	if got, want := strings.Join(finalized, ","), "local,data"; got != want {
+		t.Errorf("release() finalized %q, want %q", got, want)
+	}
+	if data.locals != nil || data.data != nil {
+		t.Errorf("release() kept the values of the environment")
+	}
+}
+
+func TestTypeFields(t *testing.T) {
+	type Base struct {
+		ID   int `json:"id"`
+		Name string
+	}
+	type Item struct {
+		Base
+		Name    string `json:",omitempty"`
+		Skipped bool   `json:"-"`
+		hidden  int
+		Tags    []string
+	}
+	var names []string
+	for _, field := range cachedFields(reflect.TypeOf(Item{})) {
+		names = append(names, field.name)
+	}
+	if got, want := strings.Join(names, ","), "Name,Tags,id"; got != want {
+		t.Errorf("typeFields() = %q, want %q", got, want)
+	}
+	if !cachedFields(reflect.TypeOf(Item{}))[0].omitEmpty {
+		t.Errorf("typeFields() ignored omitempty")
+	}
+}
+
+func TestDominantFields(t *testing.T) {
+	type Left struct {
+		ID    int
+		Label string
+	}
+	type Right struct {
+		ID    int
+		Label string `json:"Label"`
+	}
+	type Deep struct {
+		Left
+	}
+	type Pair struct {
+		Left
+		Right
+		Deep
+	}
+	var names []string
+	for _, field := range cachedFields(reflect.TypeOf(Pair{})) {
+		names = append(names, field.name+fmt.Sprint(field.index))
+	}
+	// ID is ambiguous, also for the deeper ID, and the tagged Label dominates.
+	if got, want := strings.Join(names, ","), "Label[1 1]"; got != want {
+		t.Errorf("cachedFields() = %q, want %q", got, want)
+	}
+}
+
+type embeddedInner struct {
+	Size int
+}
+
+type testNode struct {
+	*embeddedInner
+	*testNode
+	Name string
+	Next *testNode
+}
+
+func TestEmbeddedPointers(t *testing.T) {
+	var names []string
+	for _, field := range cachedFields(reflect.TypeOf(testNode{})) {
+		names = append(names, field.name)
+	}
+	if got, want := strings.Join(names, ","), "Name,Next,Size"; got != want {
+		t.Errorf("typeFields() = %q, want %q", got, want)
+	}
+	rv := reflect.ValueOf(&testNode{}).Elem()
+	size := cachedFields(rv.Type())[2]
+	if _, ok := fieldByIndex(rv, size.index, true); ok {
+		t.Errorf("fieldByIndex() allocated a pointer to an unexported struct")
+	}
+	if got := embeddedType(rv.Type(), size.index); got != reflect.TypeOf(embeddedInner{}) {
+		t.Errorf("embeddedType() = %s", got)
+	}
+	node := &testNode{Name: "loop"}
+	node.Next = node
+	if _, err := toJS(nil, reflect.ValueOf(node), "node"); err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Errorf("toJS() of a cyclic value = %v", err)
+	}
+}
+
+func TestConversionErrorPath(t *testing.T) {
+	path := fieldPath(indexPath(fieldPath(indexPath("args", 0), "items"), 3), "id")
+	err := conversionError(path, "expected number")
+	if got, want := err.Error(), "args[0].items[3].id: expected number"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	var conv *ConversionError
+	if !errors.As(err, &conv) || conv.Path != path {
+		t.Errorf("errors.As() did not return the path of the value")
+	}
+	if got, want := conversionError("", "expected array").Error(), "expected array"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestGoFuncSignature(t *testing.T) {
+	f := newGoFunc("sum", func(env Env, prefix string, values ...float64) (string, error) {
+		return prefix, nil
+	})
+	if !f.withEnv || !f.variadic || !f.hasValue || !f.hasError || len(f.params) != 2 {
+		t.Errorf("newGoFunc() = %+v", f)
+	}
+	if got, want := f.arity(), "at least 1 argument"; got != want {
+		t.Errorf("arity() = %q, want %q", got, want)
+	}
+	if got, want := newGoFunc("add", func(a, b int) int { return a + b }).arity(), "2 arguments"; got != want {
+		t.Errorf("arity() = %q, want %q", got, want)
+	}
+	for _, fn := range []interface{}{nil, 42, func() (int, int) { return 0, 0 }} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("newGoFunc(%T) did not panic", fn)
+				}
+			}()
+			newGoFunc("invalid", fn)
+		}()
+	}
+}
+
+type testCounter struct {
+	Step   int
+	URL    string `json:"href"`
+	Notify func()
+	count  int
+}
+
+func newTestCounter(step int) *testCounter { return &testCounter{Step: step} }
+
+func (c *testCounter) Increment() int { c.count += c.Step; return c.count }
+
+func (c *testCounter) ResetID(env Env) {}
+
+// Pair is not supported by Export, so it is not a method of the class.
+func (c *testCounter) Pair() (int, int) { return c.count, c.Step }
+
+func TestJSName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Name":        "name",
+		"ID":          "id",
+		"URLPath":     "urlPath",
+		"HTTP2Server": "http2Server",
+		"X":           "x",
+	} {
+		if got := jsName(name); got != want {
+			t.Errorf("jsName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestClassType(t *testing.T) {
+	c := newClassType("Counter", newTestCounter)
+	var names []string
+	for _, method := range c.methods {
+		names = append(names, method.name)
+	}
+	for _, field := range c.fields {
+		names = append(names, field.name)
+	}
+	if got, want := strings.Join(names, ","), "increment,resetID,step,href"; got != want {
+		t.Errorf("newClassType() members = %q, want %q", got, want)
+	}
+	if !c.methods[1].fn.withEnv || len(c.methods[1].fn.params) != 0 {
+		t.Errorf("newClassType() did not strip the receiver and the environment")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("newClassType() accepted a constructor not returning a pointer to a struct")
+		}
+	}()
+	newClassType("Invalid", func() int { return 0 })
+}
+
+func TestPropertyDescriptor(t *testing.T) {
+	data := new(int)
+	prop := Property{
+		Name:       "size",
+		Getter:     &Caller{},
+		Setter:     &Caller{},
+		Attributes: PropertyAttributes.Enumerable | PropertyAttributes.Static,
+		Data:       unsafe.Pointer(data),
+	}
+	raw, handles, free := getRawProperties([]Property{prop, {Name: "plain"}})
+	defer free()
+	if len(raw) != 2 || len(handles) != 1 {
+		t.Fatalf("getRawProperties() = %d descriptors, %d handles", len(raw), len(handles))
+	}
+	if raw[0].method != nil || raw[0].getter == nil || raw[0].setter == nil {
+		t.Errorf("getRaw() did not set the accessors only")
+	}
+	if int(raw[0].attributes) != prop.Attributes {
+		t.Errorf("getRaw() attributes = %d, want %d", int(raw[0].attributes), prop.Attributes)
+	}
+	if got := callbackData(handle(uintptr(raw[0].data))); got != unsafe.Pointer(data) {
+		t.Errorf("callbackData() = %p, want %p", got, data)
+	}
+	if raw[1].data != nil || raw[1].getter != nil {
+		t.Errorf("getRaw() registered callbacks for a data property")
+	}
+	callbacks.remove(handle(handles[0]))
+}
+
+func TestStatusString(t *testing.T) {
+	v := reflect.ValueOf(*Statuses)
+	for i := 0; i < v.NumField(); i++ {
+		status := Status(v.Field(i).Int())
+		if got := status.String(); !strings.HasPrefix(got, "napi_") || strings.HasPrefix(got, "napi_status(") {
+			t.Errorf("Statuses.%s.String() = %q", v.Type().Field(i).Name, got)
+		}
+	}
+	if got, want := Status(Statuses.QueueFull).String(), "napi_queue_full"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := Status(1000).String(), "napi_status(1000)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	err := &Error{Status: Status(Statuses.ObjectExpected), Message: "An object was expected"}
+	if !errors.Is(err, ErrObjectExpected) || errors.Is(err, ErrInvalidArg) || errors.Is(err, ErrPendingException) {
+		t.Errorf("errors.Is() does not match the status of %v", err)
+	}
+	if got, want := err.Error(), "napi: An object was expected (napi_object_expected)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	err.PendingException = true
+	if !errors.Is(err, ErrPendingException) || !errors.Is(err, ErrObjectExpected) {
+		t.Errorf("errors.Is() does not match the pending exception of %v", err)
+	}
+	wrapped := &ConversionError{Path: "args[0]", Msg: err.Error(), Err: err}
+	var napiErr *Error
+	if !errors.Is(wrapped, ErrObjectExpected) || !errors.As(wrapped, &napiErr) || napiErr != err {
+		t.Errorf("ConversionError does not unwrap to %v", err)
+	}
+}
+
+func TestErrorVariants(t *testing.T) {
+	if err := errorOf(Status(Statuses.OK)); err != nil {
+		t.Errorf("errorOf(napi_ok) = %v", err)
+	}
+	if err := errorOf(Status(Statuses.Closing)); !errors.Is(err, ErrClosing) {
+		t.Errorf("errorOf(napi_closing) = %v", err)
+	}
+	if _, err := GetUndefinedE(nil); err != nil {
+		t.Errorf("GetUndefinedE() error = %v", err)
+	}
+}
+
+func TestJSError(t *testing.T) {
+	cause := &JSError{Name: "Error", Message: "disk full", Code: "ENOSPC"}
+	err := &JSError{Name: "TypeError", Message: "write failed", Cause: cause}
+	if got, want := err.Error(), "TypeError: write failed"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := cause.Error(), "Error [ENOSPC]: disk full"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := (&JSError{Message: "42"}).Error(), "42"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := (&JSError{Code: "ERR_X", Message: "msg"}).Error(), "[ERR_X]: msg"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	wrapped := &ConversionError{Path: "result", Msg: err.Error(), Err: err}
+	var jsErr *JSError
+	if !errors.As(wrapped, &jsErr) || jsErr != err {
+		t.Errorf("errors.As() did not find the JSError")
+	}
+	if !errors.Is(wrapped, cause) {
+		t.Errorf("errors.Is() did not find the cause")
+	}
+}
+
+func TestErrorWrappers(t *testing.T) {
+	base := errors.New("width must be positive")
+	err := WithCode(RangeError(base), "ERR_OUT_OF_RANGE")
+	var class JSErrorClass
+	var coder JSErrorCoder
+	if !errors.As(err, &class) || class.JSErrorClass() != "RangeError" {
+		t.Errorf("RangeError() does not choose the class")
+	}
+	if !errors.As(err, &coder) || coder.JSErrorCode() != "ERR_OUT_OF_RANGE" {
+		t.Errorf("WithCode() does not set the code")
+	}
+	if err.Error() != base.Error() || !errors.Is(err, base) {
+		t.Errorf("wrappers changed the error %q", err)
+	}
+	var conv error = &ConversionError{Path: "args[0]", Msg: "expected number"}
+	if !errors.As(conv, &class) || class.JSErrorClass() != "TypeError" {
+		t.Errorf("ConversionError is not a TypeError")
+	}
+}
+
+type testValidationError struct{ Field string }
+
+func (e *testValidationError) Error() string { return "invalid " + e.Field }
+
+func TestRegisterErrorClass(t *testing.T) {
+	class := &ErrorClass{Name: "ValidationError"}
+	RegisterErrorClass(&testValidationError{}, class)
+	if got := errorClassOf(&testValidationError{Field: "name"}); got != class {
+		t.Errorf("errorClassOf() = %v, want %v", got, class)
+	}
+	if got := errorClassOf(errors.New("other")); got != nil {
+		t.Errorf("errorClassOf() = %v for an unregistered type", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterErrorClass() accepted a type registered twice")
+		}
+	}()
+	RegisterErrorClass(&testValidationError{}, &ErrorClass{Name: "Other"})
+}
+
+func TestTypedArrayKinds(t *testing.T) {
+	for arrayType, kind := range typedArrayKinds {
+		got, ok := typedArrayTypeOf(kind)
+		if !ok {
+			t.Errorf("typedArrayTypeOf(%s) is not supported", kind)
+			continue
+		}
+		if arrayType != TypedArrayTypes.UInt8ClampedArray && int(got) != arrayType {
+			t.Errorf("typedArrayTypeOf(%s) = %d, want %d", kind, got, arrayType)
+		}
+	}
+	if _, ok := typedArrayTypeOf(reflect.Int); ok {
+		t.Errorf("typedArrayTypeOf(int) is supported")
+	}
+	data := []float64{1, 2, 3, 4}
+	var view []float64
+	setSlice(unsafe.Pointer(&view), unsafe.Pointer(&data[1]), 2)
+	view[0] = 5
+	if len(view) != 2 || cap(view) != 2 || data[1] != 5 {
+		t.Errorf("setSlice() = %v, sharing %v", view, data)
+	}
+}
+
+func TestTypedArraySlices(t *testing.T) {
+	// The stub library describes every typed array as an empty Int8Array.
+	if data, status := GetInt8Array(nil, nil); status != Status(Statuses.OK) || len(data) != 0 {
+		t.Errorf("GetInt8Array() = %v, %v", data, status)
+	}
+	if _, status := GetFloat64Array(nil, nil); status != Status(Statuses.InvalidArg) {
+		t.Errorf("GetFloat64Array() of an Int8Array = %v", status)
+	}
+	if _, status := GetBigUInt64Array(nil, nil); status != Status(Statuses.InvalidArg) {
+		t.Errorf("GetBigUInt64Array() of an Int8Array = %v", status)
+	}
+	for _, slice := range []interface{}{&[]int16{}, &[]float32{}, &[]uint64{}} {
+		AllocExternalSlice(slice, 3)
+		rv := reflect.ValueOf(slice).Elem()
+		size := 3 * int(rv.Type().Elem().Size())
+		if rv.Len() != 3 || !externalAllocations.contains(rv.Pointer(), size) {
+			t.Errorf("AllocExternalSlice(%T) = %d elements", slice, rv.Len())
+		}
+		FreeExternal(rv.Interface())
+	}
+}
+
+func TestExternalMemoryRelease(t *testing.T) {
+	data := AllocExternal(6)
+	copy(data, "shared")
+	var got []byte
+	memory := &externalMemory{
+		data:    unsafe.Pointer(&data[0]),
+		length:  int64(len(data)),
+		release: bytesRelease(data, func(data []byte) { got = data }),
+	}
+	sharedMemory.add(memory)
+	memory.finalize(nil, unsafe.Pointer(&data[0]), nil)
+	if string(got) != "shared" {
+		t.Errorf("finalize() released %q", got)
+	}
+	FreeExternal(data)
+	if externalAllocations.contains(uintptr(memory.data), 1) {
+		t.Errorf("FreeExternal() kept the allocation")
+	}
+}
+
+func TestSharedMemory(t *testing.T) {
+	goData := make([]byte, 8)
+	if externalAllocations.contains(uintptr(unsafe.Pointer(&goData[0])), 8) {
+		t.Errorf("Go memory is shareable")
+	}
+	data := AllocExternal(8)
+	memory := &externalMemory{data: unsafe.Pointer(&data[0]), length: 8}
+	if !externalAllocations.contains(uintptr(memory.data), 8) || externalAllocations.contains(uintptr(memory.data), 9) {
+		t.Errorf("contains() does not match the allocation size")
+	}
+	if !sharedMemory.add(memory) || sharedMemory.add(&externalMemory{data: memory.data}) {
+		t.Errorf("add() shared the same memory twice")
+	}
+	if got := sharedMemory.get(uintptr(memory.data)); got != memory {
+		t.Errorf("get() = %v, want %v", got, memory)
+	}
+	// The memory is freed by the finalizer, as it has no release callback.
+	memory.finalize(nil, nil, nil)
+	if sharedMemory.get(uintptr(memory.data)) != nil || externalAllocations.contains(uintptr(memory.data), 8) {
+		t.Errorf("finalize() did not free the memory")
+	}
+}
+
+func TestBigWords(t *testing.T) {
+	x, _ := new(big.Int).SetString("-170141183460469231731687303715884105727", 10)
+	words := bigWords(x)
+	if len(words) != 2 || words[0] != math.MaxUint64 || words[1] != math.MaxInt64 {
+		t.Errorf("bigWords(%s) = %x", x, words)
+	}
+	if got := setBigWords(new(big.Int), words); got.CmpAbs(x) != 0 {
+		t.Errorf("setBigWords(%x) = %s, want %s", words, got, new(big.Int).Abs(x))
+	}
+}
+
+func TestDateMillis(t *testing.T) {
+	tests := []struct {
+		time time.Time
+		ms   float64
+	}{
+		{time.Unix(0, 0), 0},
+		{time.Unix(1, 999999999), 1999},
+		{time.Unix(-1, 999999), -1000},
+		{time.Unix(8.64e12, 0), 8.64e15},
+	}
+	for _, tt := range tests {
+		ms, ok := timeMillis(tt.time)
+		if !ok || ms != tt.ms {
+			t.Errorf("timeMillis(%s) = %v, %v, want %v", tt.time, ms, ok, tt.ms)
+		}
+		if got, ok := millisTime(tt.ms); !ok || !got.Equal(tt.time.Truncate(time.Millisecond)) {
+			t.Errorf("millisTime(%v) = %s, %v", tt.ms, got, ok)
+		}
+	}
+	if _, ok := timeMillis(time.Unix(8.64e12+1, 0)); ok {
+		t.Errorf("timeMillis() accepted a time out of range")
+	}
+	if _, ok := millisTime(math.NaN()); ok {
+		t.Errorf("millisTime() accepted an Invalid Date")
+	}
+	if got, _ := millisTime(-1); got.Location() != time.UTC || got.UnixNano() != -int64(time.Millisecond) {
+		t.Errorf("millisTime(-1) = %s", got)
+	}
+}
+
+func TestStringEncodings(t *testing.T) {
+	str := "a\x00é😀"
+	units := appendUTF16(nil, str)
+	if len(units) != 5 || units[3] != 0xd83d || units[4] != 0xde00 {
+		t.Errorf("appendUTF16(%q) = %x", str, units)
+	}
+	if got := string(appendUTF16Runes(nil, units)); got != str {
+		t.Errorf("appendUTF16Runes() = %q, want %q", got, str)
+	}
+	if got := string(appendUTF16Runes(nil, []uint16{'x', 0xd800, 'y'})); got != "x�y" {
+		t.Errorf("appendUTF16Runes() = %q for an unpaired surrogate", got)
+	}
+	latin1, err := EncodeLatin1("a\x00éÿ")
+	if err != nil || string(latin1) != "a\x00\xe9\xff" {
+		t.Errorf("EncodeLatin1() = %q, %v", latin1, err)
+	}
+	if got := string(appendLatin1Runes(nil, latin1)); got != "a\x00éÿ" {
+		t.Errorf("appendLatin1Runes() = %q", got)
+	}
+	for _, s := range []string{"ab€", "ab\xff"} {
+		var latin1Err *Latin1Error
+		if _, err := EncodeLatin1(s); !errors.As(err, &latin1Err) || latin1Err.Offset != 2 {
+			t.Errorf("EncodeLatin1(%q) error = %v", s, err)
+		}
+	}
+}
+
+func TestCallContextMissingArgs(t *testing.T) {
+	ctx := &CallContext{loaded: true}
+	ctx.args = ctx.inline[:0]
+	if ctx.Len() != 0 || !ctx.Arg(0).IsUndefined() || !ctx.Arg(-1).IsUndefined() {
+		t.Errorf("missing arguments are not undefined")
+	}
+	if n, err := ctx.Arg(1).Int(3); n != 3 || err != nil {
+		t.Errorf("Arg(1).Int(3) = %d, %v", n, err)
+	}
+	if s, err := ctx.Arg(2).String("anon"); s != "anon" || err != nil {
+		t.Errorf("Arg(2).String() = %q, %v", s, err)
+	}
+	if err := ctx.Arg(0).Into(0); err == nil || !strings.Contains(err.Error(), "args[0]") {
+		t.Errorf("Into(0) error = %v", err)
+	}
+}
+
+func TestValuesPointer(t *testing.T) {
+	if valuesPointer(nil) != nil || valuesPointer([]Value{}) != nil {
+		t.Errorf("valuesPointer() of an empty slice is not nil")
+	}
+	values := make([]Value, 2)
+	if unsafe.Pointer(valuesPointer(values)) != unsafe.Pointer(&values[0]) {
+		t.Errorf("valuesPointer() is not the address of the first value")
+	}
+}
+
+func TestCallIntoResult(t *testing.T) {
+	var fn Function
+	for _, result := range []interface{}{0, (*int)(nil)} {
+		// The result is checked before anything is converted or called, so
+		// the unsupported argument is never reached.
+		var conv *ConversionError
+		if err := fn.CallInto(result, nil, make(chan int)); !errors.As(err, &conv) || !strings.Contains(err.Error(), "non-nil pointer") {
+			t.Errorf("CallInto(%T) error = %v", result, err)
+		}
+	}
+	var conv *ConversionError
+	if _, err := fn.New(make(chan int)); !errors.As(err, &conv) || conv.Path != "args[0]" {
+		t.Errorf("New() error = %v", err)
+	}
+}
+
+func TestNewFunctionLength(t *testing.T) {
+	live := GetRegistryStats().Live
+	cb := FunctionCallback(func(ctx *CallContext) Value { return nil })
+	if _, status := NewFunction(nil, "f", -1, cb); status != Status(Statuses.InvalidArg) || GetRegistryStats().Live != live {
+		t.Errorf("NewFunction() with a negative length = %v", status)
+	}
+}
+
+func TestCleanupHookHandles(t *testing.T) {
+	live := GetRegistryStats().Live
+	hook, status := AddEnvCleanupHook(nil, func() {})
+	if status != Status(Statuses.OK) || hook == nil || GetRegistryStats().Live != live+1 {
+		t.Fatalf("AddEnvCleanupHook() = %v, %v", hook, status)
+	}
+	if status := RemoveEnvCleanupHook(nil, hook); status != Status(Statuses.OK) || GetRegistryStats().Live != live {
+		t.Errorf("RemoveEnvCleanupHook() = %v, keeping the hook", status)
+	}
+	async, status := AddAsyncCleanupHook(nil, func(done func()) { done() })
+	if status != Status(Statuses.OK) || async == nil || GetRegistryStats().Live != live+1 {
+		t.Fatalf("AddAsyncCleanupHook() = %v, %v", async, status)
+	}
+	if status := RemoveAsyncCleanupHook(async); status != Status(Statuses.OK) || GetRegistryStats().Live != live {
+		t.Errorf("RemoveAsyncCleanupHook() = %v, keeping the hook", status)
+	}
+	if status := RemoveAsyncCleanupHook(async); status != Status(Statuses.InvalidArg) {
+		t.Errorf("RemoveAsyncCleanupHook() of a removed hook = %v", status)
+	}
+	if status := RemoveEnvCleanupHook(nil, nil); status != Status(Statuses.InvalidArg) {
+		t.Errorf("RemoveEnvCleanupHook(nil) = %v", status)
 	}
 }
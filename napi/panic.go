@@ -0,0 +1,99 @@
+package napi
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Go panics
+// A panic raised by a Go callback must never unwind through the C frames of
+// N-API and the JavaScript engine, because that would terminate the whole
+// process. Every exported trampoline recovers the panic and reports it to
+// JavaScript as an Error with the following shape:
+//  message: The value passed to panic.
+//  code: Always PanicErrorCode.
+//  goStack: The stack trace of the goroutine that panicked.
+// When the callback was invoked by JavaScript code the error is thrown, so it
+// can be handled with a try...catch statement. Otherwise (finalizers,
+// asynchronous work and thread-safe functions) the error is passed to
+// FatalException and triggers an 'uncaughtException' in JavaScript.
+
+// PanicErrorCode is the code set on the JavaScript errors created from a
+// recovered Go panic.
+const PanicErrorCode = "ERR_GO_PANIC"
+
+// panicMessage returns the message for the JavaScript error that represents
+// the recovered value.
+func panicMessage(recovered interface{}) string {
+	switch value := recovered.(type) {
+	case error:
+		return value.Error()
+	case string:
+		return value
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// createPanicError creates the JavaScript Error that represents the recovered
+// value.
+func createPanicError(env Env, recovered interface{}, stack []byte) (Value, Status) {
+	msg, status := CreateStringUtf8(env, panicMessage(recovered))
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	code, status := CreateStringUtf8(env, PanicErrorCode)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	err, status := CreateError(env, msg, code)
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	goStack, status := CreateStringUtf8(env, string(stack))
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	return err, SetNamedProperty(env, err, "goStack", goStack)
+}
+
+// throwPanic throws the recovered value as a JavaScript exception. If the
+// exception cannot be thrown it falls back to FatalException.
+func throwPanic(env Env, recovered interface{}, stack []byte) {
+	err, status := createPanicError(env, recovered, stack)
+	if status == Status(Statuses.OK) && Throw(env, err) == Status(Statuses.OK) {
+		return
+	}
+	if ThrowError(env, panicMessage(recovered), PanicErrorCode) != Status(Statuses.OK) {
+		fatalPanic(env, recovered, stack)
+	}
+}
+
+// fatalPanic reports the recovered value through FatalException, to be used
+// where throwing an exception is not possible.
+func fatalPanic(env Env, recovered interface{}, stack []byte) {
+	if pending, _ := IsExceptionPending(env); pending {
+		GetAndClearLastException(env)
+	}
+	err, status := createPanicError(env, recovered, stack)
+	if status == Status(Statuses.OK) && FatalException(env, err) == Status(Statuses.OK) {
+		return
+	}
+	FatalError("napi", "go panic: "+panicMessage(recovered)+"\n"+string(stack))
+}
+
+// recoverThrow recovers a panic and throws it as a JavaScript exception. It
+// must be deferred directly by the trampoline.
+func recoverThrow(env Env) {
+	if recovered := recover(); recovered != nil {
+		throwPanic(env, recovered, debug.Stack())
+	}
+}
+
+// recoverFatal recovers a panic and reports it through FatalException. It must
+// be deferred directly by the trampoline.
+func recoverFatal(env Env) {
+	if recovered := recover(); recovered != nil {
+		fatalPanic(env, recovered, debug.Stack())
+	}
+}
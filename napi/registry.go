@@ -0,0 +1,95 @@
+package napi
+
+import (
+	"sync"
+)
+
+// Callback registry
+// N-API only knows about C function pointers, while add-ons are written with
+// Go callbacks. To bridge the two, every Go callback handed to N-API is stored
+// in a registry and identified by a handle. The handle is passed to N-API as
+// the data pointer of the call and the trampolines defined in gonapi.cc use it
+// to route the invocation back to the right Go callback. In this way every
+// function, method, constructor, finalizer, asynchronous work and thread-safe
+// function gets its own dispatch entry.
+// The registry owns the Go callbacks: C only sees integer handles so no Go
+// pointer is ever stored by the native side. Every entry is released as soon as
+// the JavaScript value or the native resource that uses it goes away.
+
+// handle identifies an entry of the registry. The zero value is never used
+// for a valid entry.
+type handle uintptr
+
+// registry is a concurrency safe table of the Go values referenced by the
+// native side.
+type registry struct {
+	mu       sync.RWMutex
+	next     handle
+	entries  map[handle]interface{}
+	released uint64
+}
+
+// callbacks is the registry that stores all the Go callbacks used by N-API.
+var callbacks = &registry{
+	entries: make(map[handle]interface{}),
+}
+
+// add stores the value into the registry and returns the handle that
+// identifies it.
+func (r *registry) add(value interface{}) handle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	r.entries[r.next] = value
+	return r.next
+}
+
+// get returns the value identified by the handle.
+func (r *registry) get(h handle) (interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	value, ok := r.entries[h]
+	return value, ok
+}
+
+// remove deletes the value identified by the handle from the registry. It is
+// safe to remove the same handle more than once.
+func (r *registry) remove(h handle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[h]; ok {
+		delete(r.entries, h)
+		r.released++
+	}
+}
+
+// stats returns the usage statistics of the registry.
+func (r *registry) stats() RegistryStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return RegistryStats{
+		Live:       len(r.entries),
+		Registered: uint64(r.next),
+		Released:   r.released,
+	}
+}
+
+// RegistryStats contains the usage statistics of the registry that keeps the
+// Go callbacks referenced by N-API.
+// Live: Number of callbacks currently referenced by N-API.
+// Registered: Total number of callbacks ever registered.
+// Released: Total number of callbacks released because the JavaScript value or
+// the native resource that used them has gone away.
+type RegistryStats struct {
+	Live       int
+	Registered uint64
+	Released   uint64
+}
+
+// GetRegistryStats function returns the usage statistics of the registry that
+// keeps the Go callbacks referenced by N-API. It can be used to check that
+// callbacks are released once the JavaScript functions, objects or native
+// resources that use them are gone.
+func GetRegistryStats() RegistryStats {
+	return callbacks.stats()
+}
@@ -0,0 +1,162 @@
+package napi
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Strings
+// Go strings hold UTF-8 text, that is converted to and from the three
+// encodings supported by N-API:
+//  - UTF-8, with CreateStringUtf8 and GetValueStringUtf8, is the fastest way
+//    to exchange text and the one used by ToJS and FromJS;
+//  - UTF-16, with CreateStringUtf16 and GetValueStringUtf16, that are the
+//    native encoding of JavaScript. Characters out of the Basic Multilingual
+//    Plane become surrogate pairs;
+//  - Latin-1, with CreateStringLatin1 and GetValueStringLatin1, that only
+//    represents the runes from U+0000 to U+00FF.
+// Strings are passed with their length, so they can contain NUL characters.
+// Invalid UTF-8 sequences in Go strings, and unpaired surrogates in JavaScript
+// strings, become U+FFFD. The buffers used for the conversions are reused, so
+// that converting a string only allocates the result.
+
+// Latin1Error reports a rune that cannot be encoded in Latin-1. An invalid
+// UTF-8 sequence is reported as U+FFFD, like every rune above U+00FF.
+type Latin1Error struct {
+	// Rune is the rune that cannot be encoded.
+	Rune rune
+	// Offset is the position of the rune in the string, in bytes.
+	Offset int
+}
+
+func (e *Latin1Error) Error() string {
+	return fmt.Sprintf("napi: rune %U at offset %d cannot be encoded in Latin-1", e.Rune, e.Offset)
+}
+
+// EncodeLatin1 function encodes a string in Latin-1 (ISO-8859-1), returning
+// a *Latin1Error for the first rune that cannot be encoded.
+// [in] str: The UTF-8 string to encode.
+func EncodeLatin1(str string) ([]byte, error) {
+	return appendLatin1(make([]byte, 0, len(str)), str)
+}
+
+// appendLatin1 appends the Latin-1 encoding of str to buf.
+func appendLatin1(buf []byte, str string) ([]byte, error) {
+	for i, r := range str {
+		if r > 0xff {
+			return buf, &Latin1Error{Rune: r, Offset: i}
+		}
+		buf = append(buf, byte(r))
+	}
+	return buf, nil
+}
+
+// appendUTF16 appends the UTF-16 encoding of str to buf.
+func appendUTF16(buf []uint16, str string) []uint16 {
+	for _, r := range str {
+		if r >= 0x10000 {
+			r1, r2 := utf16.EncodeRune(r)
+			buf = append(buf, uint16(r1), uint16(r2))
+			continue
+		}
+		buf = append(buf, uint16(r))
+	}
+	return buf
+}
+
+// appendLatin1Runes appends to buf the UTF-8 encoding of the Latin-1 text.
+func appendLatin1Runes(buf []byte, text []byte) []byte {
+	for _, c := range text {
+		if c < utf8.RuneSelf {
+			buf = append(buf, c)
+			continue
+		}
+		buf = append(buf, 0xc0|c>>6, 0x80|c&0x3f)
+	}
+	return buf
+}
+
+// appendUTF16Runes appends to buf the UTF-8 encoding of the UTF-16 text.
+func appendUTF16Runes(buf []byte, text []uint16) []byte {
+	var enc [utf8.UTFMax]byte
+	for i := 0; i < len(text); i++ {
+		r := rune(text[i])
+		switch {
+		case r < utf8.RuneSelf:
+			buf = append(buf, byte(r))
+			continue
+		case utf16.IsSurrogate(r):
+			if i+1 < len(text) {
+				if dec := utf16.DecodeRune(r, rune(text[i+1])); dec != utf8.RuneError {
+					r = dec
+					i++
+					break
+				}
+			}
+			r = utf8.RuneError
+		}
+		n := utf8.EncodeRune(enc[:], r)
+		buf = append(buf, enc[:n]...)
+	}
+	return buf
+}
+
+// maxPooledBuffer is the capacity above which buffers are not reused, so that
+// a single long string does not keep its buffer allocated.
+const maxPooledBuffer = 64 << 10
+
+var byteBuffers = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+var utf16Buffers = sync.Pool{
+	New: func() interface{} { return new([]uint16) },
+}
+
+// getBytes returns a reusable buffer of length n.
+func getBytes(n int) *[]byte {
+	buf := byteBuffers.Get().(*[]byte)
+	if cap(*buf) < n {
+		*buf = make([]byte, n)
+	}
+	*buf = (*buf)[:n]
+	return buf
+}
+
+// putBytes makes the buffer available for reuse.
+func putBytes(buf *[]byte) {
+	if cap(*buf) <= maxPooledBuffer {
+		byteBuffers.Put(buf)
+	}
+}
+
+// getUTF16 returns a reusable buffer of length n.
+func getUTF16(n int) *[]uint16 {
+	buf := utf16Buffers.Get().(*[]uint16)
+	if cap(*buf) < n {
+		*buf = make([]uint16, n)
+	}
+	*buf = (*buf)[:n]
+	return buf
+}
+
+// putUTF16 makes the buffer available for reuse.
+func putUTF16(buf *[]uint16) {
+	if cap(*buf)*2 <= maxPooledBuffer {
+		utf16Buffers.Put(buf)
+	}
+}
+
+// emptyString is passed to N-API in place of the data of empty strings.
+var emptyString = [1]byte{}
+
+// stringData returns a pointer to the bytes of str, that are not copied.
+func stringData(str string) unsafe.Pointer {
+	if len(str) == 0 {
+		return unsafe.Pointer(&emptyString[0])
+	}
+	return *(*unsafe.Pointer)(unsafe.Pointer(&str))
+}
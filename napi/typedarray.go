@@ -0,0 +1,335 @@
+package napi
+
+/*
+#include <stdlib.h>
+#include "gonapi.h"
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Typed arrays as Go slices
+// The elements of a typed array can be accessed from Go without copying them,
+// through a slice that shares the memory of the typed array:
+//  samples, status := napi.GetFloat64Array(env, value)
+//  for i := range samples {
+//  	samples[i] *= gain
+//  }
+// The slice starts at the first element of the typed array, whatever its byte
+// offset in the ArrayBuffer is. It is only valid while the typed array is
+// reachable from JavaScript, usually until the callback returns, and while its
+// ArrayBuffer is not detached: it must not be retained or used by other
+// goroutines. Go slices become typed arrays with CreateTypedArrayFromSlice,
+// that copies them. The slices allocated by C with AllocExternalSlice can also
+// be shared with CreateExternalTypedArray, as memory allocated by Go cannot be
+// kept by JavaScript. The element types map as follows:
+//  Int8Array          []int8
+//  UInt8Array         []uint8
+//  UInt8ClampedArray  []uint8
+//  Int16Array         []int16
+//  UInt16Array        []uint16
+//  Int32Array         []int32
+//  UInt32Array        []uint32
+//  Float32Array       []float32
+//  Float64Array       []float64
+//  BigInt64Array      []int64
+//  BigUInt64Array     []uint64
+// FromJS also converts typed arrays to Go slices and arrays, copying the
+// elements at once when their types match.
+
+// typedArrayKinds maps the types of typed arrays to the kinds of their
+// elements in Go.
+var typedArrayKinds = map[int]reflect.Kind{
+	TypedArrayTypes.Int8Array:         reflect.Int8,
+	TypedArrayTypes.UInt8Array:        reflect.Uint8,
+	TypedArrayTypes.UInt8ClampedArray: reflect.Uint8,
+	TypedArrayTypes.Int16Array:        reflect.Int16,
+	TypedArrayTypes.UInt16Array:       reflect.Uint16,
+	TypedArrayTypes.Int32Array:        reflect.Int32,
+	TypedArrayTypes.UInt32Array:       reflect.Uint32,
+	TypedArrayTypes.Float32Array:      reflect.Float32,
+	TypedArrayTypes.Float64Array:      reflect.Float64,
+	TypedArrayTypes.BigInt64Array:     reflect.Int64,
+	TypedArrayTypes.BigUInt64Array:    reflect.Uint64,
+}
+
+// typedArrayTypeOf returns the type of the typed arrays whose elements have
+// the Go kind k.
+func typedArrayTypeOf(k reflect.Kind) (TypedArrayType, bool) {
+	switch k {
+	case reflect.Int8:
+		return TypedArrayType(TypedArrayTypes.Int8Array), true
+	case reflect.Uint8:
+		return TypedArrayType(TypedArrayTypes.UInt8Array), true
+	case reflect.Int16:
+		return TypedArrayType(TypedArrayTypes.Int16Array), true
+	case reflect.Uint16:
+		return TypedArrayType(TypedArrayTypes.UInt16Array), true
+	case reflect.Int32:
+		return TypedArrayType(TypedArrayTypes.Int32Array), true
+	case reflect.Uint32:
+		return TypedArrayType(TypedArrayTypes.UInt32Array), true
+	case reflect.Float32:
+		return TypedArrayType(TypedArrayTypes.Float32Array), true
+	case reflect.Float64:
+		return TypedArrayType(TypedArrayTypes.Float64Array), true
+	case reflect.Int64:
+		return TypedArrayType(TypedArrayTypes.BigInt64Array), true
+	case reflect.Uint64:
+		return TypedArrayType(TypedArrayTypes.BigUInt64Array), true
+	}
+	return 0, false
+}
+
+// GetInt8Array function returns the elements of an Int8Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Int8Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetInt8Array(env Env, value Value) ([]int8, Status) {
+	var res []int8
+	status := typedArraySlice(env, value, TypedArrayTypes.Int8Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetUInt8Array function returns the elements of an Uint8Array, including
+// Node.js Buffers.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Uint8Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetUInt8Array(env Env, value Value) ([]uint8, Status) {
+	var res []uint8
+	status := typedArraySlice(env, value, TypedArrayTypes.UInt8Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetUInt8ClampedArray function returns the elements of an Uint8ClampedArray.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Uint8ClampedArray.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetUInt8ClampedArray(env Env, value Value) ([]uint8, Status) {
+	var res []uint8
+	status := typedArraySlice(env, value, TypedArrayTypes.UInt8ClampedArray, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetInt16Array function returns the elements of an Int16Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Int16Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetInt16Array(env Env, value Value) ([]int16, Status) {
+	var res []int16
+	status := typedArraySlice(env, value, TypedArrayTypes.Int16Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetUInt16Array function returns the elements of an Uint16Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Uint16Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetUInt16Array(env Env, value Value) ([]uint16, Status) {
+	var res []uint16
+	status := typedArraySlice(env, value, TypedArrayTypes.UInt16Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetInt32Array function returns the elements of an Int32Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Int32Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetInt32Array(env Env, value Value) ([]int32, Status) {
+	var res []int32
+	status := typedArraySlice(env, value, TypedArrayTypes.Int32Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetUInt32Array function returns the elements of an Uint32Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Uint32Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetUInt32Array(env Env, value Value) ([]uint32, Status) {
+	var res []uint32
+	status := typedArraySlice(env, value, TypedArrayTypes.UInt32Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetFloat32Array function returns the elements of a Float32Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Float32Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetFloat32Array(env Env, value Value) ([]float32, Status) {
+	var res []float32
+	status := typedArraySlice(env, value, TypedArrayTypes.Float32Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetFloat64Array function returns the elements of a Float64Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The Float64Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetFloat64Array(env Env, value Value) ([]float64, Status) {
+	var res []float64
+	status := typedArraySlice(env, value, TypedArrayTypes.Float64Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetBigInt64Array function returns the elements of a BigInt64Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The BigInt64Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetBigInt64Array(env Env, value Value) ([]int64, Status) {
+	var res []int64
+	status := typedArraySlice(env, value, TypedArrayTypes.BigInt64Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// GetBigUInt64Array function returns the elements of a BigUint64Array.
+// [in] env: The environment that the API is invoked under.
+// [in] value: The BigUint64Array.
+// Returns napi_invalid_arg if value is a typed array of another type.
+func GetBigUInt64Array(env Env, value Value) ([]uint64, Status) {
+	var res []uint64
+	status := typedArraySlice(env, value, TypedArrayTypes.BigUInt64Array, unsafe.Pointer(&res))
+	return res, status
+}
+
+// typedArraySlice points the slice to the elements of the typed array, after
+// checking that it has the expected type. The slice is left nil for empty
+// typed arrays.
+func typedArraySlice(env Env, value Value, arrayType int, slice unsafe.Pointer) Status {
+	_, t, length, data, _, status := GetTypedArrayInfo(env, value)
+	if status != Status(Statuses.OK) {
+		return status
+	}
+	if int(t) != arrayType {
+		return Status(Statuses.InvalidArg)
+	}
+	if length > 0 && data != nil {
+		setSlice(slice, data, int(length))
+	}
+	return status
+}
+
+// setSlice points the slice, of any element type, to length elements starting
+// at data.
+func setSlice(slice unsafe.Pointer, data unsafe.Pointer, length int) {
+	header := (*reflect.SliceHeader)(slice)
+	header.Data = uintptr(data)
+	header.Len = length
+	header.Cap = length
+}
+
+// sliceInfo returns the type of the typed arrays that can hold the elements of
+// the slice, the number of elements and their size in bytes.
+func sliceInfo(slice interface{}) (reflect.Value, TypedArrayType, int, bool) {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return rv, 0, 0, false
+	}
+	t, ok := typedArrayTypeOf(rv.Type().Elem().Kind())
+	return rv, t, int(rv.Type().Elem().Size()), ok
+}
+
+// CreateTypedArrayFromSlice function creates a typed array holding a copy of
+// the elements of a Go slice. The type of the typed array follows from the
+// type of the elements, as described for GetFloat64Array and the similar
+// functions: []uint8 becomes an Uint8Array.
+// [in] env: The environment that the API is invoked under.
+// [in] slice: The slice to copy, for example a []float64.
+// Returns napi_invalid_arg if the elements of slice cannot be held by a typed
+// array.
+func CreateTypedArrayFromSlice(env Env, slice interface{}) (Value, Status) {
+	rv, t, size, ok := sliceInfo(slice)
+	if !ok {
+		return nil, Status(Statuses.InvalidArg)
+	}
+	n := rv.Len()
+	arraybuffer, data, status := CreateArrayBuffer(env, uint(n*size))
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	if n > 0 {
+		// The view has the same type as the slice, so that the elements are
+		// copied at once.
+		view := reflect.New(rv.Type())
+		setSlice(unsafe.Pointer(view.Pointer()), data, n)
+		reflect.Copy(view.Elem(), rv)
+	}
+	return CreateTypedArray(env, t, uint(n), arraybuffer, 0)
+}
+
+// CreateExternalTypedArray function creates a typed array over the elements
+// of a slice. The slices allocated by AllocExternalSlice are shared without
+// copying them: changes made by Go to the elements are seen by JavaScript, and
+// the other way round, so the slice must not be modified by other goroutines
+// while JavaScript may access it, and the memory is freed once the typed array
+// and its ArrayBuffer are garbage-collected. Any other slice, like one
+// allocated by Go, is copied as by CreateTypedArrayFromSlice. The type of the
+// typed array follows from the type of the elements, as for
+// CreateTypedArrayFromSlice.
+// [in] env: The environment that the API is invoked under.
+// [in] slice: The slice to share, for example a []float64.
+// Returns napi_invalid_arg if the elements of slice cannot be held by a typed
+// array. The memory is accounted as described for CreateGoArrayBuffer.
+func CreateExternalTypedArray(env Env, slice interface{}) (Value, Status) {
+	rv, t, size, ok := sliceInfo(slice)
+	if !ok {
+		return nil, Status(Statuses.InvalidArg)
+	}
+	n := rv.Len()
+	if n == 0 {
+		return CreateTypedArrayFromSlice(env, slice)
+	}
+	arraybuffer, shared, status := shareExternal(env, CreateExternalArrayBuffer, unsafe.Pointer(rv.Pointer()), n*size, nil)
+	if !shared {
+		return CreateTypedArrayFromSlice(env, slice)
+	}
+	if status != Status(Statuses.OK) {
+		return nil, status
+	}
+	return CreateTypedArray(env, t, uint(n), arraybuffer, 0)
+}
+
+// AllocExternalSlice function sets the slice pointed to by slice to n zeroed
+// elements allocated with C.malloc, that CreateExternalTypedArray shares with
+// JavaScript without copying them. It panics if slice is not a pointer to a
+// slice whose elements can be held by a typed array, or if the memory cannot
+// be allocated. The memory that is not shared is freed with FreeExternal.
+// [in] slice: The pointer to the slice to set, for example a *[]float64.
+// [in] n: The number of elements to allocate.
+func AllocExternalSlice(slice interface{}, n int) {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic(fmt.Sprintf("napi: AllocExternalSlice of %T, not a pointer to a slice", slice))
+	}
+	if _, _, size, ok := sliceInfo(rv.Elem().Interface()); ok {
+		allocExternal(unsafe.Pointer(rv.Pointer()), n, size)
+		return
+	}
+	panic(fmt.Sprintf("napi: AllocExternalSlice of %T, not a slice held by typed arrays", slice))
+}
+
+// copyTypedArray copies the elements of the typed array to rv, a slice or an
+// array, if they have the same type in Go. Otherwise it only returns the
+// number of elements of the typed array, to convert them one at a time.
+func copyTypedArray(env Env, value Value, rv reflect.Value) (uint, bool, Status) {
+	_, t, length, data, _, status := GetTypedArrayInfo(env, value)
+	if status != Status(Statuses.OK) || typedArrayKinds[int(t)] != rv.Type().Elem().Kind() {
+		return length, false, status
+	}
+	n := int(length)
+	if rv.Kind() == reflect.Slice {
+		rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+	} else {
+		// The elements of the array that are not copied are reset.
+		rv.Set(reflect.Zero(rv.Type()))
+	}
+	if n > 0 && data != nil {
+		view := reflect.New(reflect.SliceOf(rv.Type().Elem()))
+		setSlice(unsafe.Pointer(view.Pointer()), data, n)
+		reflect.Copy(rv, view.Elem())
+	}
+	return length, true, status
+}